@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/yhlooo/go-tetris/pkg/bot/tbp"
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/randomizer"
+)
+
+// runBot 运行无界面游戏，通过 Tetris Bot Protocol 与外部机器人交互
+//
+// args 中选项之后的参数为机器人命令，指定时启动该命令并通过其标准输入输出交互，否则通过本进程的标准输入输出交互
+func runBot(args []string) error {
+	fs := flag.NewFlagSet("tetris bot", flag.ContinueOnError)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "Usage: tetris bot [flags] [--] [bot-command [args...]]\n\n"+
+			"Run a headless game driven by an external bot speaking the Tetris Bot Protocol (TBP).\n"+
+			"Without bot-command, messages are exchanged over stdin and stdout.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	mode := fs.String("mode", tetris.ModeEndless.Name, "Game mode, one of "+modeNames())
+	seed := fs.Uint64("seed", 0, seedUsage)
+	randomizerName := fs.String("randomizer", randomizer.Name7Bag, randomizerUsage)
+	maxPieces := fs.Int("max-pieces", 0, "Maximum number of pieces to place, 0 means unlimited")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	gameOpts := tetris.DefaultOptions
+	gameMode, ok := findMode(*mode)
+	if !ok {
+		return fmt.Errorf("unknown game mode %q, must be one of %s", *mode, modeNames())
+	}
+	gameOpts.Mode = gameMode
+	gameOpts.Seed = *seed
+	if err := checkRandomizer(*randomizerName); err != nil {
+		return err
+	}
+	gameOpts.RandomizerName = *randomizerName
+	frontend := tbp.NewFrontend(tbp.Options{
+		Game:      gameOpts,
+		MaxPieces: *maxPieces,
+	})
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var r io.Reader = os.Stdin
+	var w io.Writer = os.Stdout
+	var cmd *exec.Cmd
+	if fs.NArg() > 0 {
+		cmd = exec.CommandContext(ctx, fs.Arg(0), fs.Args()[1:]...)
+		cmd.Stderr = os.Stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("get bot stdin error: %w", err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("get bot stdout error: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("start bot error: %w", err)
+		}
+		defer func() { _ = cmd.Wait() }()
+		defer func() { _ = stdin.Close() }()
+		r, w = stdout, stdin
+	}
+
+	result, err := frontend.Run(ctx, r, w)
+	if err != nil {
+		return err
+	}
+	gameResult := result.Frame.Result.String()
+	if result.Frame.TopOut != tetris.TopOutNone {
+		gameResult += " (" + result.Frame.TopOut.String() + ")"
+	}
+	log.Printf(
+		"bot %q placed %d pieces, score: %d, lines: %d, level: %d, result: %s",
+		result.Bot.Name, result.Pieces, result.Frame.Score, result.Frame.ClearLines, result.Frame.Level,
+		gameResult,
+	)
+	return nil
+}
+
+// findMode 根据名称查找内置游戏模式，不区分大小写
+func findMode(name string) (tetris.GameMode, bool) {
+	for _, mode := range tetris.BuiltinGameModes {
+		if strings.EqualFold(mode.Name, name) {
+			return mode, true
+		}
+	}
+	return tetris.GameMode{}, false
+}
+
+// modeNames 返回内置游戏模式名列表
+func modeNames() string {
+	names := make([]string, len(tetris.BuiltinGameModes))
+	for i, mode := range tetris.BuiltinGameModes {
+		names[i] = mode.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -1,14 +1,94 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+	"github.com/yhlooo/go-tetris/pkg/tetris/randomizer"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
 	"github.com/yhlooo/go-tetris/pkg/ui/tty"
 )
 
 func main() {
-	ui := tty.NewGameUI()
+	if len(os.Args) > 1 && os.Args[1] == "bot" {
+		// 无界面机器人模式
+		if err := runBot(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Usage: tetris [flags]\n       tetris bot [flags] [--] [bot-command [args...]]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	randomizerName := flag.String("randomizer", randomizer.Name7Bag, randomizerUsage)
+	rotationSystemName := flag.String("rotation", rotationsystems.NameSRS, rotationSystemUsage)
+	pieceSet := flag.String("pieces", pieces.NameTetromino, pieceSetUsage)
+	seed := flag.Uint64("seed", 0, seedUsage)
+	flag.Parse()
+	if err := checkRandomizer(*randomizerName); err != nil {
+		log.Fatal(err)
+	}
+	if !slices.Contains(rotationsystems.Names, *rotationSystemName) {
+		log.Fatalf("unknown rotation system %q, must be one of %s", *rotationSystemName, strings.Join(rotationsystems.Names, ", "))
+	}
+	pieceSetName, err := loadPieceSet(*pieceSet)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ui := tty.NewGameUI(tty.Options{
+		Randomizer:     *randomizerName,
+		RotationSystem: *rotationSystemName,
+		PieceSet:       pieceSetName,
+		Seed:           *seed,
+	})
 	if err := ui.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// randomizerUsage 随机生成器选项说明
+var randomizerUsage = "Piece randomizer, one of " + strings.Join(randomizer.Names, ", ")
+
+// rotationSystemUsage 旋转系统选项说明
+var rotationSystemUsage = "Rotation system, one of " + strings.Join(rotationsystems.Names, ", ") + " (srs: guideline, ars: TGM, nrs: NES)"
+
+// pieceSetUsage 方块集合选项说明
+var pieceSetUsage = "Piece set, one of " + strings.Join(pieces.Names, ", ") + ", or path to a piece set JSON file"
+
+// seedUsage 随机种子选项说明
+const seedUsage = "Random seed, games with the same seed get the same piece sequence, 0 means random"
+
+// checkRandomizer 检查随机生成器名是否合法
+func checkRandomizer(name string) error {
+	if !slices.Contains(randomizer.Names, name) {
+		return fmt.Errorf("unknown randomizer %q, must be one of %s", name, strings.Join(randomizer.Names, ", "))
+	}
+	return nil
+}
+
+// loadPieceSet 加载方块集合，返回集合名
+//
+// name 为内置集合名时直接返回，否则作为方块集合 JSON 文件路径加载
+func loadPieceSet(name string) (string, error) {
+	if slices.Contains(pieces.Names, name) {
+		return name, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("unknown piece set %q, must be one of %s or a piece set file: %w",
+			name, strings.Join(pieces.Names, ", "), err)
+	}
+	set, err := pieces.Load(data)
+	if err != nil {
+		return "", fmt.Errorf("load piece set from %q error: %w", name, err)
+	}
+	return set.Name, nil
+}
@@ -0,0 +1,180 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// Options 机器人选项
+type Options struct {
+	// 局面评估权重
+	Weights Weights
+	// 旋转系统，需与游戏使用的旋转系统一致
+	RotationSystem rotationsystems.RotationSystem
+	// 是否使用暂存
+	UseHold bool
+	// 每次操作的间隔
+	Delay time.Duration
+
+	Logger logr.Logger
+}
+
+// DefaultOptions 默认选项
+var DefaultOptions = Options{
+	Weights:        DefaultWeights,
+	RotationSystem: rotationsystems.SuperRotationSystem{},
+	UseHold:        true,
+	Delay:          50 * time.Millisecond,
+}
+
+// Complete 补全选项
+func (opts *Options) Complete() {
+	if opts.Weights == (Weights{}) {
+		opts.Weights = DefaultWeights
+	}
+	if opts.RotationSystem == nil {
+		opts.RotationSystem = rotationsystems.SuperRotationSystem{}
+	}
+}
+
+// New 创建 Bot
+func New(opts Options) *Bot {
+	opts.Complete()
+	return &Bot{
+		weights:        opts.Weights,
+		rotationSystem: opts.RotationSystem,
+		useHold:        opts.UseHold,
+		delay:          opts.Delay,
+		logger:         opts.Logger,
+	}
+}
+
+// Bot 基于启发式局面评估的俄罗斯方块机器人
+type Bot struct {
+	weights        Weights
+	rotationSystem rotationsystems.RotationSystem
+	useHold        bool
+	delay          time.Duration
+	logger         logr.Logger
+}
+
+// Think 根据游戏帧选择最佳放置方案
+//
+// canHold 表示当前方块是否可以暂存。没有可放置的位置时返回 ok=false
+func (b *Bot) Think(frame tetris.Frame, canHold bool) (placement Placement, ok bool) {
+	field := ToField(frame.Field)
+	active := field.ActiveTetromino()
+	if active == nil {
+		return Placement{}, false
+	}
+
+	for _, p := range Placements(field, b.rotationSystem, b.weights) {
+		if !ok || p.Score > placement.Score {
+			placement = p
+			ok = true
+		}
+	}
+
+	if !canHold || !b.useHold {
+		return placement, ok
+	}
+
+	// 评估暂存后的方块
+	alt := common.TetrominoNone
+	switch {
+	case frame.HoldingTetromino != nil:
+		alt = *frame.HoldingTetromino
+	case len(frame.NextTetrominoes) > 0:
+		alt = frame.NextTetrominoes[0]
+	}
+	if alt == common.TetrominoNone || alt == active.Type {
+		return placement, ok
+	}
+	spawn, spawned := tetris.SpawnTetromino(field, b.rotationSystem, alt, frame.VisibleRows)
+	if !spawned {
+		return placement, ok
+	}
+	altField := field.Clone()
+	altField.ChangeActiveTetromino(&spawn)
+	for _, p := range Placements(altField, b.rotationSystem, b.weights) {
+		if !ok || p.Score > placement.Score {
+			placement = Placement{Tetromino: p.Tetromino, Hold: true, Ops: []tetris.Op{tetris.OpHold}, Score: p.Score}
+			ok = true
+		}
+	}
+	return placement, ok
+}
+
+// Play 操作游戏直到游戏结束
+//
+// 游戏需已开始。 ctx 被取消时返回 ctx.Err() 。
+// 每次操作前根据当前帧重新计算到达放置位置的路径，方块因下落等原因无法到达该位置或已被锁定时重新选择放置方案
+func (b *Bot) Play(ctx context.Context, t tetris.Tetris) error {
+	canHold := true
+	for {
+		if t.State() == tetris.StateFinished {
+			return nil
+		}
+
+		frame := t.CurrentFrame()
+		placement, ok := b.Think(frame, canHold)
+		if !ok {
+			return fmt.Errorf("no available placement")
+		}
+		b.logger.V(1).Info(fmt.Sprintf("bot placement: %+v, score: %.2f", placement.Tetromino, placement.Score))
+
+		if placement.Hold {
+			if err := b.wait(ctx); err != nil {
+				return err
+			}
+			t.Input(tetris.OpHold)
+			canHold = false
+			continue
+		}
+
+		pieces := frame.Stats.Pieces
+		for {
+			if err := b.wait(ctx); err != nil {
+				return err
+			}
+			frame = t.CurrentFrame()
+			if frame.GameOver || frame.Stats.Pieces != pieces {
+				// 方块已被锁定
+				canHold = true
+				break
+			}
+			ops, ok := Route(ToField(frame.Field), b.rotationSystem, placement.Tetromino, placement.TSpin)
+			if !ok {
+				break
+			}
+			t.Input(ops[0])
+		}
+	}
+}
+
+// wait 等待一次操作的间隔
+//
+// ctx 被取消时返回 ctx.Err()
+func (b *Bot) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(b.delay):
+		return nil
+	}
+}
+
+// ToField 将场读出器转换为可操作的场
+func ToField(reader common.FieldReader) *common.BitField {
+	if field, ok := reader.(*common.BitField); ok {
+		return field.Clone()
+	}
+	return common.NewBitFieldFromReader(reader)
+}
@@ -0,0 +1,255 @@
+package bot_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/bot"
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/randomizer"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// newField 创建测试用的场
+//
+// rows 从上往下每行一个字符串， '#' 表示已填充的格子， '.' 表示空格子
+func newField(rows []string, active *common.Tetromino) *common.BitField {
+	field := common.NewBitField(len(rows), len(rows[0]), nil)
+	for i, row := range rows {
+		for j := range row {
+			if row[j] == '#' {
+				field.SetTetromino(len(rows)-1-i, j, common.Garbage)
+			}
+		}
+	}
+	if active != nil {
+		field.ChangeActiveTetromino(active)
+	}
+	return field
+}
+
+// emptyRows 返回 n 行 m 列的空场
+func emptyRows(n, m int) []string {
+	rows := make([]string, n)
+	for i := range rows {
+		for j := 0; j < m; j++ {
+			rows[i] += "."
+		}
+	}
+	return rows
+}
+
+// TestEvaluate 测试局面特征计算
+func TestEvaluate(t *testing.T) {
+	field := newField([]string{
+		"......",
+		"#.....",
+		"#.#...",
+		"###..#",
+	}, nil)
+	got := bot.Evaluate(field, 1)
+	want := bot.Features{
+		// 各列高度为 3 1 2 0 0 1
+		AggregateHeight: 7,
+		Holes:           0,
+		Bumpiness:       2 + 1 + 2 + 0 + 1,
+		// 第 2 、 4 、 5 列为井
+		Wells:      1 + 0 + 0 + 0 + 0 + 0,
+		ClearLines: 1,
+	}
+	if got != want {
+		t.Errorf("features: %+v, expected %+v", got, want)
+	}
+
+	holed := newField([]string{
+		"##..",
+		".#..",
+		"#...",
+	}, nil)
+	if got := bot.Evaluate(holed, 0).Holes; got != 2 {
+		t.Errorf("holes: %d, expected 2", got)
+	}
+}
+
+// TestPlacements 测试枚举空场中的放置方案
+func TestPlacements(t *testing.T) {
+	cases := []struct {
+		tetromino common.TetrominoType
+		want      int
+	}{
+		// 占用格子相同的方向只保留一个
+		{tetromino: common.O, want: 9},
+		{tetromino: common.I, want: 7 + 10},
+		{tetromino: common.T, want: 8 + 9 + 8 + 9},
+		{tetromino: common.S, want: 8 + 9},
+	}
+	srs := rotationsystems.SuperRotationSystem{}
+	for _, c := range cases {
+		t.Run(c.tetromino.String(), func(t *testing.T) {
+			field := newField(emptyRows(20, 10), &common.Tetromino{Type: c.tetromino, Row: 16, Column: 3})
+			placements := bot.Placements(field, srs, bot.DefaultWeights)
+			if len(placements) != c.want {
+				t.Errorf("placements: %d, expected %d", len(placements), c.want)
+			}
+			for _, p := range placements {
+				if n := len(p.Ops); n == 0 || p.Ops[n-1] != tetris.OpHardDrop {
+					t.Errorf("placement %+v ops %v not end with hard drop", p.Tetromino, p.Ops)
+				}
+			}
+		})
+	}
+}
+
+// TestThink 测试选择最佳放置方案
+func TestThink(t *testing.T) {
+	rows := emptyRows(16, 10)
+	rows = append(rows,
+		"#########.",
+		"#########.",
+		"#########.",
+		"#########.",
+	)
+	field := newField(rows, &common.Tetromino{Type: common.I, Row: 16, Column: 3})
+	b := bot.New(bot.DefaultOptions)
+	placement, ok := b.Think(tetris.Frame{Field: field, VisibleRows: 20}, false)
+	if !ok {
+		t.Fatalf("no placement")
+	}
+	if placement.Features.ClearLines != 4 {
+		t.Errorf("placement %+v clear lines: %d, expected 4", placement.Tetromino, placement.Features.ClearLines)
+	}
+
+	// 暂存后的方块更好时选择暂存
+	o := field.Clone()
+	o.ChangeActiveTetromino(&common.Tetromino{Type: common.O, Row: 16, Column: 3})
+	placement, ok = b.Think(tetris.Frame{Field: o, VisibleRows: 20, NextTetrominoes: []common.TetrominoType{common.I}}, true)
+	if !ok {
+		t.Fatalf("no placement")
+	}
+	if !placement.Hold || len(placement.Ops) != 1 || placement.Ops[0] != tetris.OpHold {
+		t.Errorf("placement: %+v, expected hold", placement)
+	}
+}
+
+// TestRoute 测试计算到达指定位置的操作序列
+func TestRoute(t *testing.T) {
+	rows := emptyRows(17, 10)
+	rows = append(rows,
+		"..........",
+		"#####.####",
+		"#####.####",
+	)
+	srs := rotationsystems.SuperRotationSystem{}
+	cases := []struct {
+		name   string
+		target common.Tetromino
+		wantOK bool
+	}{
+		{
+			name:   "reachable",
+			target: common.Tetromino{Type: common.T, Row: 1, Column: 4, Dir: common.Dir2},
+			wantOK: true,
+		},
+		{
+			// 占用格子相同的其它方向
+			name:   "same-cells",
+			target: common.Tetromino{Type: common.I, Row: 0, Column: 4, Dir: common.DirL},
+			wantOK: true,
+		},
+		{
+			name:   "blocked",
+			target: common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			field := newField(rows, &common.Tetromino{Type: c.target.Type, Row: 16, Column: 3})
+			ops, ok := bot.Route(field, srs, c.target, common.TSpinNone)
+			if ok != c.wantOK {
+				t.Fatalf("ok: %v, expected %v", ok, c.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			// 依次执行操作后应落在目标位置
+			for _, op := range ops {
+				switch op {
+				case tetris.OpMoveLeft:
+					field.MoveActiveTetromino(0, -1)
+				case tetris.OpMoveRight:
+					field.MoveActiveTetromino(0, 1)
+				case tetris.OpRotateRight:
+					srs.RotateRight(field)
+				case tetris.OpRotateLeft:
+					srs.RotateLeft(field)
+				case tetris.OpRotate180:
+					srs.Rotate180(field)
+				case tetris.OpHardDrop:
+					for field.MoveActiveTetromino(-1, 0) {
+					}
+				default:
+					t.Fatalf("unexpected op: %s", op)
+				}
+			}
+			got := field.ActiveTetromino().Cells()
+			want := c.target.Cells()
+			for _, cell := range want {
+				found := false
+				for _, g := range got {
+					found = found || g == cell
+				}
+				if !found {
+					t.Fatalf("landed cells: %v, expected %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestPlay 测试机器人持续操作游戏
+func TestPlay(t *testing.T) {
+	opts := tetris.DefaultOptions
+	opts.Clock = tetris.ManualClock{}
+	opts.Randomizer = randomizer.New7Bag(rand.NewPCG(1, 1))
+	game := tetris.NewTetris(opts)
+	if err := game.Start(t.Context()); err != nil {
+		t.Fatalf("start game error: %v", err)
+	}
+	defer func() { _ = game.Stop() }()
+
+	botOpts := bot.DefaultOptions
+	botOpts.Delay = 0
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- bot.New(botOpts).Play(ctx, game)
+	}()
+
+	deadline := time.After(30 * time.Second)
+	for game.CurrentFrame().Stats.Pieces < 50 {
+		select {
+		case err := <-done:
+			t.Fatalf("play stopped after %d pieces: %v", game.CurrentFrame().Stats.Pieces, err)
+		case <-deadline:
+			t.Fatalf("timeout, placed %d pieces", game.CurrentFrame().Stats.Pieces)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("play error: %v, expected %v", err, context.Canceled)
+	}
+
+	frame := game.CurrentFrame()
+	if frame.GameOver {
+		t.Errorf("game over after %d pieces", frame.Stats.Pieces)
+	}
+	if frame.ClearLines < 15 {
+		t.Errorf("clear lines: %d, expected at least 15", frame.ClearLines)
+	}
+}
@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// Weights 局面评估权重
+//
+// 局面评分为各项特征值与对应权重乘积之和，分数越高局面越好
+type Weights struct {
+	// 各列高度之和
+	AggregateHeight float64 `json:"aggregateHeight"`
+	// 空洞数，即各列最高方块之下的空格数
+	Holes float64 `json:"holes"`
+	// 凹凸度，即相邻列高度差的绝对值之和
+	Bumpiness float64 `json:"bumpiness"`
+	// 井深之和，即各列比两侧（边界视为无限高）较低一侧低的格数之和
+	Wells float64 `json:"wells"`
+	// 本次放置消除的行数
+	ClearLines float64 `json:"clearLines"`
+}
+
+// DefaultWeights 默认权重
+//
+// 参考 https://codemyroad.wordpress.com/2013/04/14/tetris-ai-the-near-perfect-player/
+var DefaultWeights = Weights{
+	AggregateHeight: -0.510066,
+	Holes:           -0.35663,
+	Bumpiness:       -0.184483,
+	Wells:           -0.1,
+	ClearLines:      0.760666,
+}
+
+// Features 局面特征
+type Features struct {
+	AggregateHeight int
+	Holes           int
+	Bumpiness       int
+	Wells           int
+	ClearLines      int
+}
+
+// Score 返回按权重计算的局面评分
+func (w Weights) Score(features Features) float64 {
+	return w.AggregateHeight*float64(features.AggregateHeight) +
+		w.Holes*float64(features.Holes) +
+		w.Bumpiness*float64(features.Bumpiness) +
+		w.Wells*float64(features.Wells) +
+		w.ClearLines*float64(features.ClearLines)
+}
+
+// Evaluate 计算场上已填充方块的局面特征
+//
+// clearLines 为得到该局面时消除的行数，原样记录在返回的特征中
+func Evaluate(field common.FieldReader, clearLines int) Features {
+	rows, cols := field.Size()
+
+	// 各列高度及空洞
+	features := Features{ClearLines: clearLines}
+	heights := make([]int, cols)
+	for j := 0; j < cols; j++ {
+		for i := rows - 1; i >= 0; i-- {
+			filled, _ := field.FilledTetromino(i, j)
+			if filled == common.TetrominoNone {
+				if heights[j] > 0 {
+					features.Holes++
+				}
+				continue
+			}
+			if heights[j] == 0 {
+				heights[j] = i + 1
+			}
+		}
+		features.AggregateHeight += heights[j]
+	}
+
+	for j := 0; j < cols; j++ {
+		// 凹凸度
+		if j > 0 {
+			features.Bumpiness += abs(heights[j] - heights[j-1])
+		}
+
+		// 井深
+		left, right := rows, rows
+		if j > 0 {
+			left = heights[j-1]
+		}
+		if j < cols-1 {
+			right = heights[j+1]
+		}
+		features.Wells += max(min(left, right)-heights[j], 0)
+	}
+
+	return features
+}
+
+// abs 返回绝对值
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/movegen"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// Placement 方块放置方案
+type Placement struct {
+	// 方块锁定时的位置和方向
+	Tetromino common.Tetromino
+	// 锁定时的 T-Spin 类型
+	TSpin common.TSpinType
+	// 是否需要先暂存当前方块
+	//
+	// 为 true 时 Ops 仅包含 OpHold ，暂存后需根据新的活跃方块重新计算
+	Hold bool
+	// 到达该位置的操作序列，以 OpHardDrop 结束
+	Ops []tetris.Op
+	// 放置后的局面特征
+	Features Features
+	// 放置后的局面评分
+	Score float64
+}
+
+// Placements 枚举场上活跃方块经移动、旋转、软降可到达的所有放置方案，包括塞入和旋入的位置
+//
+// 返回的方案已按 weights 评分
+func Placements(field *common.BitField, rs rotationsystems.RotationSystem, weights Weights) []Placement {
+	active := field.ActiveTetromino()
+	if active == nil {
+		return nil
+	}
+
+	var ret []Placement
+	for _, p := range movegen.Generate(field, rs, *active, movegen.BasicMoves) {
+		landed := field.Clone()
+		locked := p.Tetromino
+		_ = landed.ChangeActiveTetromino(&locked)
+		_, clearLines, _, _ := landed.LockDown(nil)
+		features := Evaluate(landed, clearLines)
+		ret = append(ret, Placement{
+			Tetromino: p.Tetromino,
+			TSpin:     p.TSpin,
+			Ops:       pathOps(field, rs, p.Path),
+			Features:  features,
+			Score:     weights.Score(features),
+		})
+	}
+	return ret
+}
+
+// Route 计算场上活跃方块移动到 target 位置锁定所需的操作序列，以 OpHardDrop 结束
+//
+// 占用格子与 target 相同的位置均可，优先选择锁定时 T-Spin 类型为 tSpin 的路径。无法到达时返回 ok=false
+func Route(
+	field *common.BitField,
+	rs rotationsystems.RotationSystem,
+	target common.Tetromino,
+	tSpin common.TSpinType,
+) (ops []tetris.Op, ok bool) {
+	active := field.ActiveTetromino()
+	if active == nil || active.Type != target.Type {
+		return nil, false
+	}
+
+	var path []movegen.Move
+	for _, p := range movegen.Generate(field, rs, *active, movegen.BasicMoves) {
+		if !movegen.SameCells(p.Tetromino, target) {
+			continue
+		}
+		if !ok || p.TSpin == tSpin {
+			path = p.Path
+			ok = true
+		}
+		if p.TSpin == tSpin {
+			break
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	return pathOps(field, rs, path), true
+}
+
+// pathOps 将移动路径转换为游戏操作序列，以 OpHardDrop 结束
+func pathOps(field *common.BitField, rs rotationsystems.RotationSystem, path []movegen.Move) []tetris.Op {
+	scratch := field.Clone()
+	var ops []tetris.Op
+	for _, move := range path {
+		switch move {
+		case movegen.MoveLeft:
+			ops = append(ops, tetris.OpMoveLeft)
+		case movegen.MoveRight:
+			ops = append(ops, tetris.OpMoveRight)
+		case movegen.MoveRotateRight:
+			ops = append(ops, tetris.OpRotateRight)
+		case movegen.MoveRotateLeft:
+			ops = append(ops, tetris.OpRotateLeft)
+		case movegen.MoveRotate180:
+			ops = append(ops, tetris.OpRotate180)
+		case movegen.MoveSoftDrop:
+			// 每次软降一格
+			row := scratch.ActiveTetromino().Row
+			_ = movegen.Apply(scratch, rs, move)
+			for i := scratch.ActiveTetromino().Row; i < row; i++ {
+				ops = append(ops, tetris.OpSoftDrop)
+			}
+			continue
+		default:
+		}
+		_ = movegen.Apply(scratch, rs, move)
+	}
+	return append(ops, tetris.OpHardDrop)
+}
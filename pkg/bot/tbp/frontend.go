@@ -0,0 +1,271 @@
+package tbp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-logr/logr"
+
+	"github.com/yhlooo/go-tetris/pkg/bot"
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// Options 前端选项
+type Options struct {
+	// 游戏选项
+	//
+	// 时钟固定为 ManualClock ，方块只在机器人放置时移动
+	Game tetris.Options
+	// 最多放置的方块数， 0 表示不限
+	MaxPieces int
+
+	Logger logr.Logger
+}
+
+// Complete 补全选项
+func (opts *Options) Complete() {
+	opts.Game.Clock = tetris.ManualClock{}
+	if opts.Game.RotationSystem == nil {
+		rs, err := rotationsystems.New(opts.Game.RotationSystemName)
+		if err != nil {
+			opts.Logger.Info(fmt.Sprintf("WARN: %v, use %s instead", err, rotationsystems.NameSRS))
+			opts.Game.RotationSystemName = rotationsystems.NameSRS
+			rs = rotationsystems.SuperRotationSystem{}
+		}
+		opts.Game.RotationSystem = rs
+	}
+}
+
+// NewFrontend 创建前端
+func NewFrontend(opts Options) *Frontend {
+	opts.Complete()
+	return &Frontend{
+		gameOpts:       opts.Game,
+		rotationSystem: opts.Game.RotationSystem,
+		maxPieces:      opts.MaxPieces,
+		logger:         opts.Logger,
+	}
+}
+
+// Frontend Tetris Bot Protocol （ TBP ）前端
+//
+// 运行无界面游戏，通过每行一个 JSON 消息与外部机器人交互：向机器人发送局面、新方块和放置结果，按机器人的建议放置方块
+type Frontend struct {
+	gameOpts       tetris.Options
+	rotationSystem rotationsystems.RotationSystem
+	maxPieces      int
+	logger         logr.Logger
+}
+
+// Result 对局结果
+type Result struct {
+	// 机器人信息
+	Bot BotMessage
+	// 放置的方块数
+	Pieces int
+	// 结束时的游戏帧
+	Frame tetris.Frame
+}
+
+// Run 开始一局游戏，从 r 读取机器人消息，向 w 写入前端消息，直到游戏结束或达到方块数上限
+//
+// ctx 被取消时返回 ctx.Err()
+func (f *Frontend) Run(ctx context.Context, r io.Reader, w io.Writer) (Result, error) {
+	s := &session{
+		dec:    json.NewDecoder(r),
+		enc:    json.NewEncoder(w),
+		logger: f.logger,
+	}
+
+	t := tetris.NewTetris(f.gameOpts)
+	if err := t.Start(ctx); err != nil {
+		return Result{}, fmt.Errorf("start game error: %w", err)
+	}
+	defer func() { _ = t.Stop() }()
+
+	// 握手
+	info, err := s.expect(TypeInfo)
+	if err != nil {
+		return Result{}, err
+	}
+	ret := Result{Bot: info}
+	f.logger.Info(fmt.Sprintf("bot: %s %s by %s", info.Name, info.Version, info.Author))
+	if err := s.send(RulesMessage{Type: TypeRules}); err != nil {
+		return ret, err
+	}
+	if _, err := s.expect(TypeReady); err != nil {
+		return ret, err
+	}
+
+	frame := t.CurrentFrame()
+	if err := s.send(newStartMessage(frame)); err != nil {
+		return ret, err
+	}
+	for !frame.GameOver && (f.maxPieces <= 0 || ret.Pieces < f.maxPieces) {
+		if err := ctx.Err(); err != nil {
+			return ret, err
+		}
+
+		if err := s.send(SuggestMessage{Type: TypeSuggest}); err != nil {
+			return ret, err
+		}
+		suggestion, err := s.expect(TypeSuggestion)
+		if err != nil {
+			return ret, err
+		}
+		move, consumed, err := f.play(t, frame, suggestion.Moves)
+		if err != nil {
+			return ret, err
+		}
+		ret.Pieces++
+		if err := s.send(PlayMessage{Type: TypePlay, Move: move}); err != nil {
+			return ret, err
+		}
+
+		frame = t.CurrentFrame()
+		if frame.GameOver {
+			break
+		}
+		// 每使用一个方块，队列末尾出现一个新方块
+		for _, piece := range frame.NextTetrominoes[max(len(frame.NextTetrominoes)-consumed, 0):] {
+			if err := s.send(NewPieceMessage{Type: TypeNewPiece, Piece: pieceName(piece)}); err != nil {
+				return ret, err
+			}
+		}
+	}
+	ret.Frame = t.CurrentFrame()
+
+	if err := s.send(StopMessage{Type: TypeStop}); err != nil {
+		return ret, err
+	}
+	if err := s.send(QuitMessage{Type: TypeQuit}); err != nil {
+		return ret, err
+	}
+	return ret, nil
+}
+
+// play 按建议放置当前方块，返回实际使用的建议和使用的队列方块数
+//
+// 按优先级选择第一个可到达的建议。需要暂存的建议一旦选中即执行暂存，暂存后无法到达时返回错误
+func (f *Frontend) play(t tetris.Tetris, frame tetris.Frame, moves []Move) (Move, int, error) {
+	active := frame.Field.ActiveTetromino()
+	if active == nil {
+		return Move{}, 0, fmt.Errorf("no active piece")
+	}
+
+	for _, move := range moves {
+		target, err := move.Location.Tetromino()
+		if err != nil {
+			f.logger.Info(fmt.Sprintf("WARN: skip invalid move %+v: %v", move, err))
+			continue
+		}
+
+		consumed := 1
+		if target.Type != active.Type {
+			// 需要暂存
+			alt := common.TetrominoNone
+			switch {
+			case !f.gameOpts.HoldEnabled:
+			case frame.HoldingTetromino != nil:
+				alt = *frame.HoldingTetromino
+			case len(frame.NextTetrominoes) > 0:
+				alt = frame.NextTetrominoes[0]
+				consumed = 2
+			}
+			if alt != target.Type {
+				f.logger.Info(fmt.Sprintf("WARN: skip move %+v: piece not available", move))
+				continue
+			}
+			t.Input(tetris.OpHold)
+			frame = t.CurrentFrame()
+		}
+
+		ops, ok := bot.Route(bot.ToField(frame.Field), f.rotationSystem, target, spinType(move.Spin))
+		if !ok {
+			if target.Type != active.Type {
+				return move, 0, fmt.Errorf("move %+v unreachable after hold", move)
+			}
+			f.logger.Info(fmt.Sprintf("WARN: skip move %+v: unreachable", move))
+			continue
+		}
+		for _, op := range ops {
+			t.Input(op)
+		}
+		return move, consumed, nil
+	}
+	return Move{}, 0, fmt.Errorf("no valid move in suggestion: %+v", moves)
+}
+
+// newStartMessage 根据游戏帧创建开始计算消息
+func newStartMessage(frame tetris.Frame) StartMessage {
+	msg := StartMessage{
+		Type:       TypeStart,
+		Combo:      frame.Combo,
+		BackToBack: frame.BackToBack,
+	}
+	if frame.HoldingTetromino != nil {
+		hold := pieceName(*frame.HoldingTetromino)
+		msg.Hold = &hold
+	}
+	if active := frame.Field.ActiveTetromino(); active != nil {
+		msg.Queue = append(msg.Queue, pieceName(active.Type))
+	}
+	for _, piece := range frame.NextTetrominoes {
+		msg.Queue = append(msg.Queue, pieceName(piece))
+	}
+
+	rows, cols := frame.Field.Size()
+	msg.Board = make([][]*string, max(BoardRows, rows))
+	for i := range msg.Board {
+		msg.Board[i] = make([]*string, cols)
+		for j := range msg.Board[i] {
+			if cell, _ := frame.Field.FilledTetromino(i, j); cell != common.TetrominoNone {
+				name := pieceName(cell)
+				msg.Board[i][j] = &name
+			}
+		}
+	}
+	return msg
+}
+
+// session 与机器人的消息通道
+type session struct {
+	dec    *json.Decoder
+	enc    *json.Encoder
+	logger logr.Logger
+}
+
+// send 发送消息
+func (s *session) send(msg any) error {
+	if err := s.enc.Encode(msg); err != nil {
+		return fmt.Errorf("send message to bot error: %w", err)
+	}
+	return nil
+}
+
+// expect 读取消息直到读到指定类型的消息，忽略其它类型的消息
+//
+// 读到 error 消息时返回错误
+func (s *session) expect(msgType string) (BotMessage, error) {
+	for {
+		var msg BotMessage
+		if err := s.dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return msg, fmt.Errorf("bot exited while waiting for %q message", msgType)
+			}
+			return msg, fmt.Errorf("read message from bot error: %w", err)
+		}
+		switch {
+		case msg.Type == msgType:
+			return msg, nil
+		case msg.Type == TypeError:
+			return msg, fmt.Errorf("bot error: %s", msg.Reason)
+		default:
+			s.logger.V(1).Info(fmt.Sprintf("ignore %q message from bot", msg.Type))
+		}
+	}
+}
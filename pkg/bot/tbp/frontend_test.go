@@ -0,0 +1,215 @@
+package tbp_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/bot"
+	"github.com/yhlooo/go-tetris/pkg/bot/tbp"
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/movegen"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// TestPieceLocation 测试方块与协议中方块位置的相互转换
+func TestPieceLocation(t *testing.T) {
+	// T 方块朝北时旋转中心为凸起下方的格子
+	loc := tbp.NewPieceLocation(common.Tetromino{Type: common.T, Row: -1, Column: 3, Dir: common.Dir0})
+	if want := (tbp.PieceLocation{Type: "T", Orientation: tbp.OrientationNorth, X: 4, Y: 0}); loc != want {
+		t.Errorf("location: %+v, expected %+v", loc, want)
+	}
+
+	for tetrominoType := common.I; tetrominoType <= common.Z; tetrominoType++ {
+		for dir := common.Dir0; dir <= common.DirL; dir++ {
+			tetromino := common.Tetromino{Type: tetrominoType, Row: 5, Column: 3, Dir: dir}
+			got, err := tbp.NewPieceLocation(tetromino).Tetromino()
+			if err != nil {
+				t.Errorf("%s %d: convert back error: %v", tetrominoType, dir, err)
+				continue
+			}
+			if !movegen.SameCells(got, tetromino) || got.Dir != dir {
+				t.Errorf("%s %d: %+v, expected %+v", tetrominoType, dir, got, tetromino)
+			}
+		}
+	}
+
+	for _, invalid := range []tbp.PieceLocation{
+		{Type: "X", Orientation: tbp.OrientationNorth},
+		{Type: "Garbage", Orientation: tbp.OrientationNorth},
+		{Type: "T", Orientation: "up"},
+	} {
+		if _, err := invalid.Tetromino(); err == nil {
+			t.Errorf("location %+v: expected error", invalid)
+		}
+	}
+}
+
+// fakeBot 测试用的机器人，使用 bot 包选择放置位置
+type fakeBot struct {
+	dec   *json.Decoder
+	enc   *json.Encoder
+	rows  int
+	cols  int
+	board *common.BitField
+	queue []common.TetrominoType
+	// 为 true 时只给出无效的建议
+	invalid bool
+}
+
+// run 与前端交互直到收到 quit 消息或前端关闭连接
+func (b *fakeBot) run(t *testing.T) {
+	send := func(msg tbp.BotMessage) {
+		if err := b.enc.Encode(msg); err != nil {
+			t.Errorf("send message error: %v", err)
+		}
+	}
+	send(tbp.BotMessage{Type: tbp.TypeInfo, Name: "fake", Version: "0", Author: "test"})
+	for {
+		var msg struct {
+			Type  string      `json:"type"`
+			Queue []string    `json:"queue"`
+			Board [][]*string `json:"board"`
+			Piece string      `json:"piece"`
+			Move  tbp.Move    `json:"move"`
+		}
+		if err := b.dec.Decode(&msg); err != nil {
+			if err != io.EOF {
+				t.Errorf("read message error: %v", err)
+			}
+			return
+		}
+		switch msg.Type {
+		case tbp.TypeRules:
+			send(tbp.BotMessage{Type: tbp.TypeReady})
+		case tbp.TypeStart:
+			b.board = common.NewBitField(b.rows, b.cols, nil)
+			for i := 0; i < b.rows; i++ {
+				for j, cell := range msg.Board[i] {
+					if cell != nil {
+						b.board.SetTetromino(i, j, common.Garbage)
+					}
+				}
+			}
+			b.queue = nil
+			for _, name := range msg.Queue {
+				b.queue = append(b.queue, parsePiece(t, name))
+			}
+		case tbp.TypeSuggest:
+			if b.invalid {
+				send(tbp.BotMessage{Type: tbp.TypeSuggestion, Moves: []tbp.Move{
+					{Location: tbp.PieceLocation{Type: "X", Orientation: tbp.OrientationNorth}},
+				}})
+				continue
+			}
+			send(tbp.BotMessage{Type: tbp.TypeSuggestion, Moves: []tbp.Move{b.suggest(t)}})
+		case tbp.TypePlay:
+			target, err := msg.Move.Location.Tetromino()
+			if err != nil {
+				t.Errorf("invalid played move: %v", err)
+				return
+			}
+			b.board.ChangeActiveTetromino(&target)
+			b.board.LockDown(nil)
+			b.queue = b.queue[1:]
+		case tbp.TypeNewPiece:
+			b.queue = append(b.queue, parsePiece(t, msg.Piece))
+		case tbp.TypeQuit:
+			return
+		}
+	}
+}
+
+// suggest 选择当前方块的最佳放置位置
+func (b *fakeBot) suggest(t *testing.T) tbp.Move {
+	spawn := common.Tetromino{Type: b.queue[0], Row: b.rows - 3, Column: b.cols/2 - 2}
+	if spawn.Type == common.O {
+		spawn.Row, spawn.Column = b.rows-2, b.cols/2-1
+	}
+	field := b.board.Clone()
+	if !field.ChangeActiveTetromino(&spawn) {
+		t.Errorf("no room to spawn %s", spawn.Type)
+		return tbp.Move{}
+	}
+	var best bot.Placement
+	for i, p := range bot.Placements(field, rotationsystems.SuperRotationSystem{}, bot.DefaultWeights) {
+		if i == 0 || p.Score > best.Score {
+			best = p
+		}
+	}
+	return tbp.Move{Location: tbp.NewPieceLocation(best.Tetromino), Spin: tbp.SpinNone}
+}
+
+// parsePiece 解析协议中的方块名
+func parsePiece(t *testing.T, name string) common.TetrominoType {
+	var ret common.TetrominoType
+	if err := ret.UnmarshalText([]byte(name)); err != nil {
+		t.Errorf("invalid piece %q: %v", name, err)
+	}
+	return ret
+}
+
+// runFrontend 使用 fakeBot 运行前端
+func runFrontend(t *testing.T, opts tbp.Options, fake *fakeBot) (tbp.Result, error) {
+	botIn, frontendOut := io.Pipe()
+	frontendIn, botOut := io.Pipe()
+	fake.dec = json.NewDecoder(botIn)
+	fake.enc = json.NewEncoder(botOut)
+	fake.rows, fake.cols = opts.Game.Rows, opts.Game.Columns
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() { _ = botIn.Close() }()
+		defer func() { _ = botOut.Close() }()
+		fake.run(t)
+	}()
+
+	result, err := tbp.NewFrontend(opts).Run(t.Context(), frontendIn, frontendOut)
+	_ = frontendOut.Close()
+	_ = frontendIn.Close()
+	<-done
+	return result, err
+}
+
+// TestFrontendRun 测试按机器人的建议放置方块
+func TestFrontendRun(t *testing.T) {
+	opts := tbp.Options{Game: tetris.DefaultOptions, MaxPieces: 30}
+	opts.Game.Seed = 1
+	opts.Game.HoldEnabled = false
+	fake := &fakeBot{}
+	result, err := runFrontend(t, opts, fake)
+	if err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if result.Bot.Name != "fake" {
+		t.Errorf("bot name: %q, expected %q", result.Bot.Name, "fake")
+	}
+	if result.Pieces != 30 || result.Frame.Stats.Pieces != 30 {
+		t.Errorf("pieces: %d (%d in game), expected 30", result.Pieces, result.Frame.Stats.Pieces)
+	}
+	if result.Frame.ClearLines == 0 {
+		t.Errorf("no lines cleared")
+	}
+
+	// 机器人根据消息维护的场与游戏一致
+	for i := 0; i < opts.Game.Rows; i++ {
+		for j := 0; j < opts.Game.Columns; j++ {
+			filled, _ := result.Frame.Field.FilledTetromino(i, j)
+			if got := fake.board.Filled(i, j); got != (filled != common.TetrominoNone) {
+				t.Fatalf("bot board (%d, %d): %v, game: %s", i, j, got, filled)
+			}
+		}
+	}
+}
+
+// TestFrontendNoValidMove 测试机器人没有给出有效建议时返回错误
+func TestFrontendNoValidMove(t *testing.T) {
+	opts := tbp.Options{Game: tetris.DefaultOptions, MaxPieces: 1}
+	opts.Game.Seed = 1
+	_, err := runFrontend(t, opts, &fakeBot{invalid: true})
+	if err == nil || !strings.Contains(err.Error(), "no valid move") {
+		t.Errorf("error: %v, expected no valid move", err)
+	}
+}
@@ -0,0 +1,240 @@
+package tbp
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// BoardRows 协议中场的行数
+//
+// 超出游戏场的行均为空
+const BoardRows = 40
+
+// 消息类型
+const (
+	// 前端发给机器人的消息
+	TypeRules    = "rules"
+	TypeStart    = "start"
+	TypeSuggest  = "suggest"
+	TypePlay     = "play"
+	TypeNewPiece = "new_piece"
+	TypeStop     = "stop"
+	TypeQuit     = "quit"
+
+	// 机器人发给前端的消息
+	TypeInfo       = "info"
+	TypeReady      = "ready"
+	TypeError      = "error"
+	TypeSuggestion = "suggestion"
+)
+
+// 方块方向
+const (
+	OrientationNorth = "north"
+	OrientationEast  = "east"
+	OrientationSouth = "south"
+	OrientationWest  = "west"
+)
+
+// 旋转类型
+const (
+	SpinNone = "none"
+	SpinMini = "mini"
+	SpinFull = "full"
+)
+
+// RulesMessage 游戏规则消息
+type RulesMessage struct {
+	Type string `json:"type"`
+}
+
+// StartMessage 开始计算消息，包含当前局面
+type StartMessage struct {
+	Type string `json:"type"`
+	// 暂存的方块，没有时为 null
+	Hold *string `json:"hold"`
+	// 方块队列，第一个为当前方块，不含暂存的方块
+	Queue []string `json:"queue"`
+	// 连击数
+	Combo int `json:"combo"`
+	// 是否处于 Back-to-Back 状态
+	BackToBack bool `json:"back_to_back"`
+	// 场，从下往上共 BoardRows 行，空的格子为 null
+	Board [][]*string `json:"board"`
+}
+
+// SuggestMessage 请求放置建议消息
+type SuggestMessage struct {
+	Type string `json:"type"`
+}
+
+// PlayMessage 放置方块消息
+type PlayMessage struct {
+	Type string `json:"type"`
+	Move Move   `json:"move"`
+}
+
+// NewPieceMessage 新方块加入队列消息
+type NewPieceMessage struct {
+	Type  string `json:"type"`
+	Piece string `json:"piece"`
+}
+
+// StopMessage 停止计算消息
+type StopMessage struct {
+	Type string `json:"type"`
+}
+
+// QuitMessage 退出消息
+type QuitMessage struct {
+	Type string `json:"type"`
+}
+
+// BotMessage 机器人发给前端的消息
+//
+// 各类型消息的字段合并在一起，只有对应类型的字段有值
+type BotMessage struct {
+	Type string `json:"type"`
+
+	// info 消息
+	Name     string   `json:"name,omitempty"`
+	Version  string   `json:"version,omitempty"`
+	Author   string   `json:"author,omitempty"`
+	Features []string `json:"features,omitempty"`
+
+	// error 消息
+	Reason string `json:"reason,omitempty"`
+
+	// suggestion 消息，按优先级从高到低排列
+	Moves []Move `json:"moves,omitempty"`
+}
+
+// Move 方块放置方案
+type Move struct {
+	Location PieceLocation `json:"location"`
+	// 旋转类型，为 SpinNone 、 SpinMini 或 SpinFull
+	Spin string `json:"spin"`
+}
+
+// PieceLocation 方块位置
+type PieceLocation struct {
+	// 方块类型
+	Type string `json:"type"`
+	// 方块方向
+	Orientation string `json:"orientation"`
+	// 旋转中心所在的列，从左往右第一列为 0
+	X int `json:"x"`
+	// 旋转中心所在的行，从下往上第一行为 0
+	Y int `json:"y"`
+}
+
+// northOffsets 各方块朝北时各格相对旋转中心的偏移 {x, y}
+var northOffsets = map[common.TetrominoType][4][2]int{
+	common.I: {{-1, 0}, {0, 0}, {1, 0}, {2, 0}},
+	common.J: {{-1, 0}, {0, 0}, {1, 0}, {-1, 1}},
+	common.L: {{-1, 0}, {0, 0}, {1, 0}, {1, 1}},
+	common.O: {{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+	common.S: {{-1, 0}, {0, 0}, {0, 1}, {1, 1}},
+	common.T: {{-1, 0}, {0, 0}, {1, 0}, {0, 1}},
+	common.Z: {{-1, 1}, {0, 1}, {0, 0}, {1, 0}},
+}
+
+// orientations 方向与协议中方向名的对应关系
+var orientations = [4]string{
+	common.Dir0: OrientationNorth,
+	common.DirR: OrientationEast,
+	common.Dir2: OrientationSouth,
+	common.DirL: OrientationWest,
+}
+
+// Tetromino 转换为方块
+//
+// 协议中的位置以旋转中心表示，与方块定位点不同，按占用的格子转换
+func (loc PieceLocation) Tetromino() (common.Tetromino, error) {
+	var tetrominoType common.TetrominoType
+	if err := tetrominoType.UnmarshalText([]byte(loc.Type)); err != nil {
+		return common.Tetromino{}, err
+	}
+	if _, ok := northOffsets[tetrominoType]; !ok {
+		return common.Tetromino{}, fmt.Errorf("invalid piece type: %q", loc.Type)
+	}
+	dir := slices.Index(orientations[:], loc.Orientation)
+	if dir < 0 {
+		return common.Tetromino{}, fmt.Errorf("invalid orientation: %q", loc.Orientation)
+	}
+
+	ret := common.Tetromino{Type: tetrominoType, Dir: common.TetrominoDir(dir)}
+	want := sortedCells(offsetCells(tetrominoType, ret.Dir, loc.X, loc.Y))
+	got := sortedCells(ret.Cells())
+	ret.Row = want[0].Row() - got[0].Row()
+	ret.Column = want[0].Column() - got[0].Column()
+	if !slices.Equal(sortedCells(ret.Cells()), want) {
+		// 形状不一致，不应发生
+		return common.Tetromino{}, fmt.Errorf("unsupported piece location: %+v", loc)
+	}
+	return ret, nil
+}
+
+// NewPieceLocation 根据方块创建方块位置
+//
+// 协议只支持七种标准方块，其它方块的位置均为 (0, 0)
+func NewPieceLocation(tetromino common.Tetromino) PieceLocation {
+	ret := PieceLocation{
+		Type:        tetromino.Type.String(),
+		Orientation: orientations[tetromino.Dir%4],
+	}
+	want := sortedCells(tetromino.Cells())
+	got := sortedCells(offsetCells(tetromino.Type, tetromino.Dir, 0, 0))
+	if len(got) == 0 || len(want) == 0 {
+		return ret
+	}
+	ret.X = want[0].Column() - got[0].Column()
+	ret.Y = want[0].Row() - got[0].Row()
+	return ret
+}
+
+// offsetCells 返回旋转中心位于 (x, y) 时方块占用的格子
+func offsetCells(tetrominoType common.TetrominoType, dir common.TetrominoDir, x, y int) []common.Location {
+	ret := make([]common.Location, len(northOffsets[tetrominoType]))
+	for i, offset := range northOffsets[tetrominoType] {
+		dx, dy := offset[0], offset[1]
+		// 每次顺时针旋转 90 度
+		for j := common.Dir0; j < dir%4; j++ {
+			dx, dy = dy, -dx
+		}
+		ret[i] = common.Location{y + dy, x + dx}
+	}
+	return ret
+}
+
+// sortedCells 按行、列排序格子
+func sortedCells(cells []common.Location) []common.Location {
+	slices.SortFunc(cells, func(a, b common.Location) int {
+		if a.Row() != b.Row() {
+			return a.Row() - b.Row()
+		}
+		return a.Column() - b.Column()
+	})
+	return cells
+}
+
+// spinType 将协议中的旋转类型转换为 T-Spin 类型
+func spinType(spin string) common.TSpinType {
+	switch spin {
+	case SpinMini:
+		return common.TSpinMini
+	case SpinFull:
+		return common.TSpinFull
+	}
+	return common.TSpinNone
+}
+
+// pieceName 返回协议中的方块名，垃圾块为 "G"
+func pieceName(tetrominoType common.TetrominoType) string {
+	if tetrominoType == common.Garbage {
+		return "G"
+	}
+	return tetrominoType.String()
+}
@@ -0,0 +1,36 @@
+package env
+
+import (
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// Action 动作
+//
+// 可以是一个操作指令，也可以是一个完整的放置方案
+type Action struct {
+	// 放置方案，不为 nil 时将当前方块（或暂存后的方块）移动到该位置并硬降，忽略 Op
+	Placement *Placement
+	// 操作指令
+	Op tetris.Op
+}
+
+// OpAction 创建操作指令动作
+func OpAction(op tetris.Op) Action {
+	return Action{Op: op}
+}
+
+// PlacementAction 创建放置方案动作
+func PlacementAction(placement Placement) Action {
+	return Action{Placement: &placement}
+}
+
+// Placement 放置方案
+type Placement struct {
+	// 是否先暂存当前方块，再放置暂存后的方块
+	Hold bool
+	// 方块锁定时的位置和方向
+	Tetromino common.Tetromino
+	// 锁定时的 T-Spin 类型
+	TSpin common.TSpinType
+}
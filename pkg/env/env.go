@@ -0,0 +1,298 @@
+package env
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yhlooo/go-tetris/pkg/bot"
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/movegen"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// eventsBufferSize 每步最多记录的事件数
+const eventsBufferSize = 1024
+
+// Options 环境选项
+type Options struct {
+	// 游戏选项
+	//
+	// 时钟固定为 ManualClock ，随机种子由 Env.Reset 指定
+	Game tetris.Options
+	// 奖励计算器
+	//
+	// 与游戏的 Scorer 在相同的时机被调用（每次软降、硬降、锁定），返回的分数累加为奖励。为 nil 时使用游戏的 Scorer ，即奖励为得分
+	Reward tetris.Scorer
+	// 每锁定一个方块的额外奖励
+	PieceReward float64
+	// 游戏失败时的额外奖励，通常为负数
+	GameOverReward float64
+	// 每个操作指令动作后推进的处理周期数，小于等于 0 时为 1
+	//
+	// 用于模拟重力和锁定延迟，放置方案动作不推进周期
+	TicketsPerOp int
+}
+
+// Complete 补全选项
+func (opts *Options) Complete() {
+	opts.Game.Clock = tetris.ManualClock{}
+	if opts.Game.Scorer == nil {
+		opts.Game.Scorer = tetris.DefaultScorer()
+	}
+	if opts.Game.RotationSystem == nil {
+		// 与游戏相同，名称无效时使用 SRS
+		rs, err := rotationsystems.New(opts.Game.RotationSystemName)
+		if err != nil {
+			opts.Game.RotationSystemName = rotationsystems.NameSRS
+			rs = rotationsystems.SuperRotationSystem{}
+		}
+		opts.Game.RotationSystem = rs
+	}
+	if opts.Reward == nil {
+		opts.Reward = opts.Game.Scorer
+	}
+	if opts.TicketsPerOp <= 0 {
+		opts.TicketsPerOp = 1
+	}
+}
+
+// New 创建环境
+func New(opts Options) *Env {
+	opts.Complete()
+	e := &Env{
+		rotationSystem: opts.Game.RotationSystem,
+		holdEnabled:    opts.Game.HoldEnabled,
+		pieceReward:    opts.PieceReward,
+		gameOverReward: opts.GameOverReward,
+		ticketsPerOp:   opts.TicketsPerOp,
+	}
+
+	// 在计算得分的同时计算奖励
+	scorer, reward := opts.Game.Scorer, opts.Reward
+	opts.Game.Scorer = func(level int, event tetris.ScoreEvent) (int, []string) {
+		r, _ := reward(level, event)
+		e.reward += float64(r)
+		return scorer(level, event)
+	}
+	e.gameOpts = opts.Game
+	return e
+}
+
+// Env 类似 Gym 的强化学习环境
+//
+// 同步运行游戏，没有后台协程，每次 Step 执行一个动作并返回新的观测、奖励和是否结束。
+//
+// 非线程安全
+type Env struct {
+	gameOpts       tetris.Options
+	rotationSystem rotationsystems.RotationSystem
+	holdEnabled    bool
+	pieceReward    float64
+	gameOverReward float64
+	ticketsPerOp   int
+
+	tetris  tetris.Tetris
+	events  *tetris.Subscription[tetris.Event]
+	canHold bool
+	reward  float64
+}
+
+// Observation 观测
+type Observation struct {
+	// 当前帧
+	Frame tetris.Frame
+	// 场上已填充的格子，从下往上，不含活跃方块
+	Board [][]bool
+	// 当前方块是否可以暂存
+	CanHold bool
+}
+
+// Info 单步的附加信息
+type Info struct {
+	// 本步发生的事件
+	Events []tetris.Event
+	// 本步得分
+	Score int
+	// 本步消除的行数
+	ClearLines int
+	// 本步锁定的方块数
+	Pieces int
+}
+
+// Reset 以指定随机种子开始新的一局并返回初始观测
+//
+// seed 为 0 时使用随机的种子。上一局未结束时直接停止
+func (e *Env) Reset(seed uint64) (Observation, error) {
+	e.Close()
+
+	opts := e.gameOpts
+	opts.Seed = seed
+	e.tetris = tetris.NewTetris(opts)
+	e.events = e.tetris.SubscribeEvents(tetris.SubscribeOptions{
+		Policy:     tetris.PolicyBuffered,
+		BufferSize: eventsBufferSize,
+	})
+	if err := e.tetris.Start(context.Background()); err != nil {
+		return Observation{}, fmt.Errorf("start game error: %w", err)
+	}
+	e.canHold = e.holdEnabled
+	e.reward = 0
+	return e.observe(), nil
+}
+
+// Step 执行一个动作，返回执行后的观测、本步奖励、本局是否结束和附加信息
+//
+// 尚未 Reset 、本局已结束或放置方案不可到达时返回错误，此时不推进游戏
+func (e *Env) Step(action Action) (obs Observation, reward float64, done bool, info Info, err error) {
+	if e.tetris == nil {
+		return Observation{}, 0, false, Info{}, fmt.Errorf("environment not reset")
+	}
+	if e.tetris.State() == tetris.StateFinished {
+		return e.observe(), 0, true, Info{}, fmt.Errorf("episode is done, call Reset to start a new one")
+	}
+
+	e.reward = 0
+	if action.Placement != nil {
+		if err := e.place(*action.Placement); err != nil {
+			return e.observe(), 0, false, Info{}, err
+		}
+	} else {
+		e.tetris.Input(action.Op)
+		if e.tetris.State() == tetris.StateRunning {
+			_ = e.tetris.Step(e.ticketsPerOp)
+		}
+	}
+
+	info = e.drainEvents()
+	obs = e.observe()
+	reward = e.reward + float64(info.Pieces)*e.pieceReward
+	if obs.Frame.GameOver && obs.Frame.Result == tetris.ResultLost {
+		reward += e.gameOverReward
+	}
+	return obs, reward, obs.Frame.GameOver, info, nil
+}
+
+// Placements 返回当前可执行的所有放置方案
+//
+// 包括当前方块经移动、旋转、软降可到达的所有位置，以及可以暂存时暂存后的方块可到达的所有位置
+func (e *Env) Placements() []Placement {
+	if e.tetris == nil || e.tetris.State() == tetris.StateFinished {
+		return nil
+	}
+	frame := e.tetris.CurrentFrame()
+	field := bot.ToField(frame.Field)
+	active := field.ActiveTetromino()
+	if active == nil {
+		return nil
+	}
+
+	var ret []Placement
+	for _, p := range movegen.Generate(field, e.rotationSystem, *active, movegen.BasicMoves) {
+		ret = append(ret, Placement{Tetromino: p.Tetromino, TSpin: p.TSpin})
+	}
+
+	if !e.canHold {
+		return ret
+	}
+	alt := common.TetrominoNone
+	switch {
+	case frame.HoldingTetromino != nil:
+		alt = *frame.HoldingTetromino
+	case len(frame.NextTetrominoes) > 0:
+		alt = frame.NextTetrominoes[0]
+	}
+	if alt == common.TetrominoNone || alt == active.Type {
+		return ret
+	}
+	spawn, ok := tetris.SpawnTetromino(field, e.rotationSystem, alt, frame.VisibleRows)
+	if !ok {
+		return ret
+	}
+	for _, p := range movegen.Generate(field, e.rotationSystem, spawn, movegen.BasicMoves) {
+		ret = append(ret, Placement{Hold: true, Tetromino: p.Tetromino, TSpin: p.TSpin})
+	}
+	return ret
+}
+
+// Close 停止当前一局
+func (e *Env) Close() {
+	if e.tetris == nil {
+		return
+	}
+	_ = e.tetris.Stop()
+	e.events.Unsubscribe()
+	e.tetris = nil
+	e.events = nil
+}
+
+// place 执行放置方案
+func (e *Env) place(placement Placement) error {
+	frame := e.tetris.CurrentFrame()
+	if placement.Hold {
+		if !e.canHold {
+			return fmt.Errorf("can not hold: already held")
+		}
+		e.tetris.Input(tetris.OpHold)
+		frame = e.tetris.CurrentFrame()
+	}
+
+	ops, ok := bot.Route(bot.ToField(frame.Field), e.rotationSystem, placement.Tetromino, placement.TSpin)
+	if !ok {
+		if placement.Hold {
+			// 已暂存，无法撤销
+			return fmt.Errorf("placement %+v unreachable after hold", placement.Tetromino)
+		}
+		return fmt.Errorf("placement %+v unreachable", placement.Tetromino)
+	}
+	for _, op := range ops {
+		e.tetris.Input(op)
+	}
+	return nil
+}
+
+// drainEvents 取出本步发生的所有事件
+func (e *Env) drainEvents() Info {
+	var info Info
+	for {
+		select {
+		case event, ok := <-e.events.C():
+			if !ok {
+				return info
+			}
+			info.Events = append(info.Events, event)
+			switch event.Type {
+			case tetris.EventScore:
+				info.Score += event.Score
+			case tetris.EventLockDown:
+				info.ClearLines += event.ClearLines
+				info.Pieces++
+				e.canHold = e.holdEnabled
+			case tetris.EventHold:
+				e.canHold = false
+			default:
+			}
+		default:
+			return info
+		}
+	}
+}
+
+// observe 返回当前观测
+func (e *Env) observe() Observation {
+	frame := e.tetris.CurrentFrame()
+	rows, cols := frame.Field.Size()
+	board := make([][]bool, rows)
+	for i := range board {
+		board[i] = make([]bool, cols)
+		for j := range board[i] {
+			cell, _ := frame.Field.FilledTetromino(i, j)
+			board[i][j] = cell != common.TetrominoNone
+		}
+	}
+	return Observation{
+		Frame:   frame,
+		Board:   board,
+		CanHold: e.canHold,
+	}
+}
@@ -0,0 +1,192 @@
+package env_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/env"
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// newEnv 创建测试用的环境并开始一局
+func newEnv(t *testing.T, opts env.Options, seed uint64) (*env.Env, env.Observation) {
+	t.Helper()
+	if opts.Game.Rows == 0 {
+		opts.Game = tetris.DefaultOptions
+	}
+	e := env.New(opts)
+	obs, err := e.Reset(seed)
+	if err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	t.Cleanup(e.Close)
+	return e, obs
+}
+
+// TestEnvNotReset 测试未 Reset 时 Step 返回错误
+func TestEnvNotReset(t *testing.T) {
+	e := env.New(env.Options{Game: tetris.DefaultOptions})
+	if _, _, _, _, err := e.Step(env.OpAction(tetris.OpHardDrop)); err == nil {
+		t.Errorf("expected error")
+	}
+	if got := e.Placements(); got != nil {
+		t.Errorf("placements: %v, expected nil", got)
+	}
+}
+
+// TestEnvReset 测试相同种子开始的对局相同
+func TestEnvReset(t *testing.T) {
+	e, obs := newEnv(t, env.Options{}, 42)
+	again, err := e.Reset(42)
+	if err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	if *obs.Frame.Field.ActiveTetromino() != *again.Frame.Field.ActiveTetromino() ||
+		!reflect.DeepEqual(obs.Frame.NextTetrominoes, again.Frame.NextTetrominoes) {
+		t.Errorf("different episodes with same seed")
+	}
+	if !obs.CanHold {
+		t.Errorf("can not hold at start")
+	}
+	if rows, cols := obs.Frame.Field.Size(); len(obs.Board) != rows || len(obs.Board[0]) != cols {
+		t.Errorf("board size: %dx%d, expected %dx%d", len(obs.Board), len(obs.Board[0]), rows, cols)
+	}
+}
+
+// TestEnvPlacements 测试枚举可执行的放置方案
+func TestEnvPlacements(t *testing.T) {
+	for _, holdEnabled := range []bool{true, false} {
+		opts := env.Options{Game: tetris.DefaultOptions}
+		opts.Game.HoldEnabled = holdEnabled
+		e, obs := newEnv(t, opts, 1)
+
+		active := obs.Frame.Field.ActiveTetromino().Type
+		holds := 0
+		for _, p := range e.Placements() {
+			if p.Hold {
+				holds++
+				if p.Tetromino.Type != obs.Frame.NextTetrominoes[0] {
+					t.Errorf("hold placement type: %s, expected %s", p.Tetromino.Type, obs.Frame.NextTetrominoes[0])
+				}
+			} else if p.Tetromino.Type != active {
+				t.Errorf("placement type: %s, expected %s", p.Tetromino.Type, active)
+			}
+		}
+		if got := holds > 0; got != holdEnabled {
+			t.Errorf("hold enabled %v: has hold placements %v", holdEnabled, got)
+		}
+	}
+}
+
+// TestEnvStepPlacement 测试执行放置方案动作
+func TestEnvStepPlacement(t *testing.T) {
+	e, _ := newEnv(t, env.Options{PieceReward: 0.5}, 1)
+	var placement env.Placement
+	for _, p := range e.Placements() {
+		if p.Hold {
+			placement = p
+			break
+		}
+	}
+	obs, reward, done, info, err := e.Step(env.PlacementAction(placement))
+	if err != nil {
+		t.Fatalf("step error: %v", err)
+	}
+	if done {
+		t.Errorf("done after one placement")
+	}
+	if info.Pieces != 1 || obs.Frame.Stats.Pieces != 1 {
+		t.Errorf("pieces: %d (%d in game), expected 1", info.Pieces, obs.Frame.Stats.Pieces)
+	}
+	// 奖励默认为得分
+	if want := float64(info.Score) + 0.5; reward != want || info.Score == 0 {
+		t.Errorf("reward: %v, score: %d, expected score + 0.5", reward, info.Score)
+	}
+	if !obs.CanHold {
+		t.Errorf("can not hold after lock down")
+	}
+
+	// 锁定的方块位于放置方案的位置
+	for _, cell := range placement.Tetromino.Cells() {
+		if !obs.Board[cell.Row()][cell.Column()] {
+			t.Errorf("cell %v not filled", cell)
+		}
+	}
+
+	// 不可到达的放置方案不推进游戏
+	unreachable := env.Placement{Tetromino: common.Tetromino{Type: obs.Frame.Field.ActiveTetromino().Type, Row: -5}}
+	if _, _, _, _, err := e.Step(env.PlacementAction(unreachable)); err == nil {
+		t.Errorf("expected unreachable error")
+	}
+	if got := e.Placements(); len(got) == 0 {
+		t.Errorf("no placements after unreachable placement")
+	}
+}
+
+// TestEnvStepOp 测试执行操作指令动作
+func TestEnvStepOp(t *testing.T) {
+	e, obs := newEnv(t, env.Options{}, 1)
+	column := obs.Frame.Field.ActiveTetromino().Column
+
+	obs, reward, _, info, err := e.Step(env.OpAction(tetris.OpMoveLeft))
+	if err != nil {
+		t.Fatalf("step error: %v", err)
+	}
+	if got := obs.Frame.Field.ActiveTetromino().Column; got != column-1 {
+		t.Errorf("active column: %d, expected %d", got, column-1)
+	}
+	if reward != 0 || info.Pieces != 0 {
+		t.Errorf("reward: %v, pieces: %d, expected 0", reward, info.Pieces)
+	}
+
+	obs, _, _, info, err = e.Step(env.OpAction(tetris.OpHold))
+	if err != nil {
+		t.Fatalf("step error: %v", err)
+	}
+	if obs.CanHold {
+		t.Errorf("can hold after hold")
+	}
+	if len(info.Events) == 0 || info.Events[0].Type != tetris.EventHold {
+		t.Errorf("events: %v, expected hold event", info.Events)
+	}
+}
+
+// TestEnvGameOver 测试游戏失败时的奖励和结束后的 Step
+func TestEnvGameOver(t *testing.T) {
+	e, _ := newEnv(t, env.Options{Reward: env.ClearLinesReward, GameOverReward: -10}, 1)
+	var (
+		reward float64
+		done   bool
+		err    error
+	)
+	for i := 0; i < 100 && !done; i++ {
+		_, reward, done, _, err = e.Step(env.OpAction(tetris.OpHardDrop))
+		if err != nil {
+			t.Fatalf("step error: %v", err)
+		}
+	}
+	if !done {
+		t.Fatalf("not done after 100 hard drops")
+	}
+	if reward != -10 {
+		t.Errorf("last reward: %v, expected -10", reward)
+	}
+	if _, _, done, _, err := e.Step(env.OpAction(tetris.OpHardDrop)); err == nil || !done {
+		t.Errorf("step after done: done %v, error %v, expected done with error", done, err)
+	}
+}
+
+// TestRewards 测试预置的奖励计算器
+func TestRewards(t *testing.T) {
+	tsd := tetris.ScoreEvent{ClearLines: 2, TSpin: common.TSpinFull}
+	if got, _ := env.ClearLinesReward(1, tsd); got != 2 {
+		t.Errorf("clear lines reward: %d, expected 2", got)
+	}
+	if got, _ := env.AttackReward(1, tsd); got != 4 {
+		t.Errorf("attack reward: %d, expected 4", got)
+	}
+	if got, _ := env.AttackReward(1, tetris.ScoreEvent{HardDrop: 10}); got != 0 {
+		t.Errorf("attack reward of hard drop: %d, expected 0", got)
+	}
+}
@@ -0,0 +1,17 @@
+package env
+
+import (
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+)
+
+// ClearLinesReward 以消除行数为奖励的奖励计算器
+func ClearLinesReward(_ int, event tetris.ScoreEvent) (int, []string) {
+	return event.ClearLines, nil
+}
+
+// AttackReward 以攻击行数为奖励的奖励计算器
+//
+// 攻击行数的计算方式与 Stats.Attack 相同
+func AttackReward(_ int, event tetris.ScoreEvent) (int, []string) {
+	return tetris.AttackLines(event), nil
+}
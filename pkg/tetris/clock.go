@@ -0,0 +1,63 @@
+package tetris
+
+import (
+	"time"
+)
+
+// Clock 时钟
+//
+// 用于驱动游戏按固定频率自动推进
+type Clock interface {
+	// NewTicker 创建按指定间隔触发的计时器
+	//
+	// 返回 nil 表示不自动推进游戏，此时需要通过 Tetris.Step 手动推进
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker 计时器
+type Ticker interface {
+	// C 返回计时器触发通道
+	C() <-chan time.Time
+	// Stop 停止计时器
+	Stop()
+}
+
+// RealClock 基于系统时间的时钟
+type RealClock struct{}
+
+var _ Clock = RealClock{}
+
+// NewTicker 创建按指定间隔触发的计时器
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+// realTicker 基于 time.Ticker 的计时器
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+var _ Ticker = realTicker{}
+
+// C 返回计时器触发通道
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+// Stop 停止计时器
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+// ManualClock 手动时钟
+//
+// 不会自动推进游戏，游戏开始后不创建后台协程，只能通过 Tetris.Step 同步推进。
+// 适用于测试、模拟、回放等需要确定性结果的场景
+type ManualClock struct{}
+
+var _ Clock = ManualClock{}
+
+// NewTicker 返回 nil ，表示不自动推进游戏
+func (ManualClock) NewTicker(time.Duration) Ticker {
+	return nil
+}
@@ -0,0 +1,144 @@
+package tetris_test
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/randomizer"
+)
+
+// scriptStep 操作脚本中的一步，推进 Wait 个周期后输入 Op ， Garbage 大于 0 时改为加入垃圾行
+type scriptStep struct {
+	Wait       int
+	Op         tetris.Op
+	Garbage    int
+	HoleColumn int
+}
+
+// scriptOps 操作脚本中使用的操作
+var scriptOps = []tetris.Op{
+	tetris.OpMoveLeft, tetris.OpMoveRight, tetris.OpRotateRight, tetris.OpRotateLeft, tetris.OpRotate180,
+	tetris.OpMoveLeftPress, tetris.OpMoveLeftRelease, tetris.OpMoveRightPress, tetris.OpMoveRightRelease,
+	tetris.OpSoftDropPress, tetris.OpSoftDropRelease, tetris.OpSoftDrop, tetris.OpHold,
+	tetris.OpHardDrop, tetris.OpHardDrop,
+}
+
+// newScript 根据种子生成 n 步操作脚本， garbage 表示是否包含加入垃圾行
+func newScript(seed uint64, n int, garbage bool) []scriptStep {
+	r := rand.New(rand.NewPCG(seed, seed))
+	script := make([]scriptStep, n)
+	for i := range script {
+		script[i] = scriptStep{Wait: r.IntN(20), Op: scriptOps[r.IntN(len(scriptOps))]}
+		if garbage && r.IntN(30) == 0 {
+			script[i].Garbage = 1 + r.IntN(2)
+			script[i].HoleColumn = r.IntN(10)
+		}
+	}
+	return script
+}
+
+// runScript 在游戏中执行操作脚本，返回每步之后的帧，游戏结束时提前返回
+func runScript(t *testing.T, game tetris.Tetris, script []scriptStep) []tetris.Frame {
+	t.Helper()
+	var frames []tetris.Frame
+	for _, step := range script {
+		if game.State() != tetris.StateRunning {
+			break
+		}
+		if err := game.Step(step.Wait); err != nil {
+			t.Fatalf("step error: %v", err)
+		}
+		if step.Garbage > 0 {
+			if err := game.AddGarbage(step.Garbage, step.HoleColumn); err != nil {
+				t.Fatalf("add garbage error: %v", err)
+			}
+		} else {
+			game.Input(step.Op)
+		}
+		frames = append(frames, game.CurrentFrame())
+	}
+	return frames
+}
+
+// seededOptions 返回使用指定种子生成方块的默认选项
+func seededOptions(seed uint64) tetris.Options {
+	opts := tetris.DefaultOptions
+	opts.Randomizer = randomizer.New7Bag(rand.NewPCG(seed, seed))
+	return opts
+}
+
+// newManualGame 创建并开始使用 ManualClock 的游戏
+func newManualGame(t *testing.T, opts tetris.Options) tetris.Tetris {
+	t.Helper()
+	opts.Clock = tetris.ManualClock{}
+	game := tetris.NewTetris(opts)
+	if err := game.Start(t.Context()); err != nil {
+		t.Fatalf("start game error: %v", err)
+	}
+	t.Cleanup(func() { _ = game.Stop() })
+	return game
+}
+
+// TestManualClockStep 测试使用 ManualClock 时相同的种子和操作得到相同的游戏过程
+func TestManualClockStep(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(opts *tetris.Options)
+	}{
+		{name: "default", modify: func(*tetris.Options) {}},
+		{name: "high-level-no-reset", modify: func(opts *tetris.Options) {
+			opts.InitialLevel = 10
+			opts.LockDownPolicy = tetris.LockDownStepReset
+		}},
+		{name: "no-hold", modify: func(opts *tetris.Options) {
+			opts.HoldEnabled = false
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			script := newScript(1, 500, true)
+			var runs [2][]tetris.Frame
+			for i := range runs {
+				opts := seededOptions(42)
+				c.modify(&opts)
+				runs[i] = runScript(t, newManualGame(t, opts), script)
+			}
+			a, b := runs[0], runs[1]
+			if len(a) != len(b) {
+				t.Fatalf("frames count mismatch: %d != %d", len(a), len(b))
+			}
+			for i := range a {
+				if !reflect.DeepEqual(a[i], b[i]) {
+					t.Fatalf("frame %d mismatch:\n%+v\n%+v", i, a[i], b[i])
+				}
+			}
+		})
+	}
+}
+
+// TestManualClockNotAdvance 测试使用 ManualClock 时游戏只通过 Step 推进
+func TestManualClockNotAdvance(t *testing.T) {
+	cases := []struct {
+		name    string
+		tickets int
+		fall    bool
+	}{
+		{name: "no-step", tickets: 0, fall: false},
+		{name: "step", tickets: 2000, fall: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			game := newManualGame(t, seededOptions(1))
+			before := *game.CurrentFrame().Field.ActiveTetromino()
+			if err := game.Step(c.tickets); err != nil {
+				t.Fatalf("step error: %v", err)
+			}
+			after := *game.CurrentFrame().Field.ActiveTetromino()
+			if got := after.Row < before.Row; got != c.fall {
+				t.Errorf("active tetromino fell: %t, expected %t (row %d -> %d)", got, c.fall, before.Row, after.Row)
+			}
+		})
+	}
+}
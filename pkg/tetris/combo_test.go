@@ -0,0 +1,105 @@
+package tetris_test
+
+import (
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// TestComboAndBackToBack 测试连击数和 Back-to-Back 的计算
+func TestComboAndBackToBack(t *testing.T) {
+	tsdRows := []string{
+		"G.........",
+		"...GGGGGGG",
+		"G.GGGGGGGG",
+	}
+	cases := []struct {
+		name        string
+		rows        []string
+		active      common.Tetromino
+		ops         []tetris.Op
+		clearStreak int
+		backToBack  bool
+		wantCombo   int
+		wantB2B     bool
+		wantScore   int
+	}{
+		{
+			name:      "first-clear",
+			rows:      tsdRows,
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			ops:       []tetris.Op{tetris.OpHardDrop},
+			wantScore: 300,
+		},
+		{
+			name:        "combo",
+			rows:        tsdRows,
+			active:      common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			ops:         []tetris.Op{tetris.OpHardDrop},
+			clearStreak: 2,
+			wantCombo:   2,
+			wantScore:   300 + 2*50,
+		},
+		{
+			name:        "combo-break",
+			active:      common.Tetromino{Type: common.T, Row: -1, Column: 3, Dir: common.Dir0},
+			ops:         []tetris.Op{tetris.OpHardDrop},
+			clearStreak: 3,
+		},
+		{
+			name:       "back-to-back",
+			rows:       tsdRows,
+			active:     common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.DirR},
+			ops:        []tetris.Op{tetris.OpRotateRight, tetris.OpHardDrop},
+			backToBack: true,
+			wantB2B:    true,
+			wantScore:  1800,
+		},
+		{
+			name:       "back-to-back-break",
+			rows:       tsdRows,
+			active:     common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			ops:        []tetris.Op{tetris.OpHardDrop},
+			backToBack: true,
+			wantScore:  300,
+		},
+		{
+			// 不消行时保持 Back-to-Back 状态
+			name:       "back-to-back-no-clear",
+			active:     common.Tetromino{Type: common.T, Row: -1, Column: 3, Dir: common.Dir0},
+			ops:        []tetris.Op{tetris.OpHardDrop},
+			backToBack: true,
+			wantB2B:    true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			setSnapshotField(&snapshot, c.rows...)
+			snapshot.ActiveTetromino = c.active
+			snapshot.ClearStreak = c.clearStreak
+			snapshot.BackToBack = c.backToBack
+			game := restoreManualGame(t, snapshot)
+			for _, op := range c.ops {
+				game.Input(op)
+			}
+
+			frame := game.CurrentFrame()
+			if frame.Combo != c.wantCombo {
+				t.Errorf("combo: %d, expected %d", frame.Combo, c.wantCombo)
+			}
+			if frame.BackToBack != c.wantB2B {
+				t.Errorf("back-to-back: %v, expected %v", frame.BackToBack, c.wantB2B)
+			}
+			if frame.Score != c.wantScore {
+				t.Errorf("score: %d, expected %d", frame.Score, c.wantScore)
+			}
+		})
+	}
+}
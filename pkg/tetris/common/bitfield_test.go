@@ -0,0 +1,165 @@
+package common_test
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// testField Field 和 BitField 共同的操作
+type testField interface {
+	common.MutableField
+	SetTetromino(row, col int, tetrominoType common.TetrominoType) bool
+	LockDown(newTetromino *common.Tetromino) (tSpin common.TSpinType, clearLines int, perfectClear bool, ok bool)
+	AddGarbage(lines, holeColumn int) bool
+	Empty() bool
+	TSpinType() common.TSpinType
+}
+
+// TestBitFieldMatchesField 测试对 BitField 和 Field 执行相同的随机操作序列后结果一致
+func TestBitFieldMatchesField(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	const rows, cols = 12, 8
+	randomTetromino := func() *common.Tetromino {
+		return &common.Tetromino{
+			Type:   common.TetrominoType(r.IntN(int(common.Z)) + 1),
+			Row:    rows - 4,
+			Column: r.IntN(cols - 3),
+			Dir:    common.TetrominoDir(r.IntN(4)),
+		}
+	}
+	newFields := func() []testField {
+		active := randomTetromino()
+		activeCopy := *active
+		return []testField{common.NewField(rows, cols, active), common.NewBitField(rows, cols, &activeCopy)}
+	}
+
+	fields := newFields()
+	for step := 0; step < 5000; step++ {
+		var results [2][]any
+		switch n := r.IntN(10); {
+		case n < 6:
+			dRow, dCol := []int{-1, 0, 0}[n%3], []int{0, -1, 1}[n%3]
+			for i, f := range fields {
+				results[i] = []any{f.MoveActiveTetromino(dRow, dCol)}
+			}
+		case n < 9:
+			next := randomTetromino()
+			for i, f := range fields {
+				nextCopy := *next
+				tSpin, lines, pc, ok := f.LockDown(&nextCopy)
+				results[i] = []any{tSpin, lines, pc, ok}
+			}
+		default:
+			lines, hole := r.IntN(3), r.IntN(cols)
+			for i, f := range fields {
+				results[i] = []any{f.AddGarbage(lines, hole)}
+			}
+		}
+
+		if !reflect.DeepEqual(results[0], results[1]) {
+			t.Fatalf("step %d: field result %v, bit field result %v", step, results[0], results[1])
+		}
+		if got, want := fieldRows(fields[1]), fieldRows(fields[0]); !slices.Equal(got, want) {
+			t.Fatalf("step %d: bit field:\n%v\nfield:\n%v", step, got, want)
+		}
+		if got, want := fields[1].Cells(), fields[0].Cells(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("step %d: cells mismatch", step)
+		}
+		if got, want := fields[1].ActiveTetromino(), fields[0].ActiveTetromino(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("step %d: active %+v, expected %+v", step, got, want)
+		}
+		if fields[1].Empty() != fields[0].Empty() || fields[1].TSpinType() != fields[0].TSpinType() {
+			t.Fatalf("step %d: empty or t-spin mismatch", step)
+		}
+
+		// 顶出后重新开始
+		if !fields[0].IsValid() || (len(results[0]) > 0 && results[0][len(results[0])-1] == false && r.IntN(4) == 0) {
+			fields = newFields()
+		}
+	}
+}
+
+// TestBitFieldCopyOnWrite 测试复制的场与原场互不影响
+func TestBitFieldCopyOnWrite(t *testing.T) {
+	field := common.NewBitFieldFromReader(parseField(t, []string{"....", "....", "....", "GG.G"}, nil))
+	clone := field.Clone()
+
+	// 修改原场不影响副本
+	field.SetTetromino(3, 0, common.T)
+	if got, want := fieldRows(clone), []string{"....", "....", "....", "GG.G"}; !slices.Equal(got, want) {
+		t.Errorf("clone after modifying origin:\n%v\nexpected:\n%v", got, want)
+	}
+
+	// 修改副本不影响原场
+	active := common.Tetromino{Type: common.I, Row: 0, Column: 0, Dir: common.DirR}
+	if !clone.ChangeActiveTetromino(&active) {
+		t.Fatalf("invalid active tetromino: %+v", active)
+	}
+	if _, lines, _, _ := clone.LockDown(nil); lines != 1 {
+		t.Errorf("clone clear lines: %d, expected 1", lines)
+	}
+	if got, want := fieldRows(field), []string{"T...", "....", "....", "GG.G"}; !slices.Equal(got, want) {
+		t.Errorf("origin after modifying clone:\n%v\nexpected:\n%v", got, want)
+	}
+	if got, want := fieldRows(clone), []string{"....", "..I.", "..I.", "..I."}; !slices.Equal(got, want) {
+		t.Errorf("clone:\n%v\nexpected:\n%v", got, want)
+	}
+
+	// 活跃方块不共享
+	field.ChangeActiveTetromino(&common.Tetromino{Type: common.O, Row: 1, Column: 0})
+	clone = field.Clone()
+	clone.MoveActiveTetromino(0, 1)
+	if got := field.ActiveTetromino().Column; got != 0 {
+		t.Errorf("origin active column: %d, expected 0", got)
+	}
+}
+
+// TestBitFieldSize 测试 BitField 的大小限制和边界
+func TestBitFieldSize(t *testing.T) {
+	field := common.NewBitField(4, 100, nil)
+	if rows, cols := field.Size(); rows != 4 || cols != common.MaxBitFieldColumns {
+		t.Errorf("size: %dx%d, expected 4x%d", rows, cols, common.MaxBitFieldColumns)
+	}
+	cases := []struct {
+		row, col int
+		want     bool
+	}{
+		{row: 0, col: 0, want: false},
+		{row: -1, col: 0, want: true},
+		{row: 0, col: -1, want: true},
+		{row: 0, col: common.MaxBitFieldColumns, want: true},
+		// 上边界之外不视为已填充
+		{row: 4, col: 0, want: false},
+	}
+	for _, c := range cases {
+		if got := field.Filled(c.row, c.col); got != c.want {
+			t.Errorf("filled (%d, %d): %v, expected %v", c.row, c.col, got, c.want)
+		}
+	}
+}
+
+// TestFieldCells 测试场上格子信息中的阴影块，获取格子信息不改变活跃方块
+func TestFieldCells(t *testing.T) {
+	rows := []string{"....", "....", "....", "....", "GG.."}
+	active := common.Tetromino{Type: common.O, Row: 3, Column: 0}
+	field := parseField(t, rows, &active)
+	bitField := common.NewBitFieldFromReader(field)
+	for _, f := range []common.FieldReader{field, bitField} {
+		cells := f.Cells()
+		for _, loc := range [][2]int{{1, 0}, {1, 1}, {2, 0}, {2, 1}} {
+			if got := cells[loc[0]][loc[1]]; got != (common.Cell{Type: common.O, Shadow: true}) {
+				t.Errorf("%T cell %v: %+v, expected O shadow", f, loc, got)
+			}
+		}
+		if got := cells[3][0]; got != (common.Cell{Type: common.O}) {
+			t.Errorf("%T cell (3, 0): %+v, expected O", f, got)
+		}
+		if got := *f.ActiveTetromino(); got != active {
+			t.Errorf("%T active after cells: %+v, expected %+v", f, got, active)
+		}
+	}
+}
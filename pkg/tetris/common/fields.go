@@ -12,6 +12,19 @@ type FieldReader interface {
 	ActiveTetromino() *Tetromino
 }
 
+// MutableField 可移动、更换活跃方块的场
+//
+// Field 和 BitField 均实现该接口，旋转系统等通过该接口操作活跃方块
+type MutableField interface {
+	FieldReader
+	// IsValid 活跃方块是否没有超出左右和下边界且不与其他方块重合
+	IsValid() bool
+	// MoveActiveTetromino 移动活跃方块，移动后不合法时不移动并返回 false
+	MoveActiveTetromino(row, col int) bool
+	// ChangeActiveTetromino 更换活跃方块，更换后不合法时不更换并返回 false
+	ChangeActiveTetromino(tetromino *Tetromino) bool
+}
+
 // Cell 格子
 type Cell struct {
 	Type   TetrominoType
@@ -51,7 +64,26 @@ type Field struct {
 	filled [][]TetrominoType
 }
 
-var _ FieldReader = &Field{}
+var _ MutableField = &Field{}
+
+// Clone 复制场，包括已填充方块和活跃方块
+func (f *Field) Clone() *Field {
+	filled := make([][]TetrominoType, len(f.filled))
+	for i, row := range f.filled {
+		filled[i] = append([]TetrominoType(nil), row...)
+	}
+	var active *Tetromino
+	if f.active != nil {
+		activeCopy := *f.active
+		active = &activeCopy
+	}
+	return &Field{
+		rows:   f.rows,
+		cols:   f.cols,
+		active: active,
+		filled: filled,
+	}
+}
 
 // Size 获取场大小
 func (f *Field) Size() (rows, cols int) {
@@ -72,19 +104,17 @@ func (f *Field) Cells() [][]Cell {
 		return ret
 	}
 
-	//添加阴影块
-	curActiveRow, curActiveCol := f.active.Row, f.active.Column
-	for f.MoveActiveTetromino(-1, 0) {
-	}
-	for _, cell := range f.active.Cells() {
+	// 添加阴影块
+	shadow := *f.active
+	shadow.Row -= f.DropDistance(shadow)
+	for _, cell := range shadow.Cells() {
 		row := cell.Row()
 		col := cell.Column()
 		if row >= 0 && row < len(ret) && col >= 0 && col < len(ret[row]) {
-			ret[row][col].Type = f.active.Type
+			ret[row][col].Type = shadow.Type
 			ret[row][col].Shadow = true
 		}
 	}
-	f.active.Row, f.active.Column = curActiveRow, curActiveCol
 
 	// 添加活跃方块
 	for _, cell := range f.active.Cells() {
@@ -181,31 +211,24 @@ func (f *Field) ChangeActiveTetromino(tetromino *Tetromino) bool {
 
 var tCorners = [4]Location{{0, 0}, {0, 2}, {2, 0}, {2, 2}}
 
+// tFrontCorners T 方块各方向下凸起一侧的两个角
+var tFrontCorners = [4][2]Location{
+	Dir0: {{2, 0}, {2, 2}},
+	DirR: {{0, 2}, {2, 2}},
+	Dir2: {{0, 0}, {0, 2}},
+	DirL: {{0, 0}, {2, 0}},
+}
+
 // LockDown 锁定当前活跃方块清除填满的行然后用新方块替换活跃方块
 //
+// tSpin 为仅根据锁定时 T 方块四角占用情况判断的 T-Spin 类型（见 TSpinType ），最后一次操作是否为旋转等条件需由调用方判断。
+// perfectClear 表示清除后场上没有任何已填充的方块。
+//
 // 若更换方块完后活跃方块没有超出边界且没有与其他方块重合则操作成功并返回 ok=true ，否则不更换方块（但仍执行钉住和清除操作）并返回 ok=false
-func (f *Field) LockDown(newTetromino *Tetromino) (tSpin bool, clearLines int, ok bool) {
+func (f *Field) LockDown(newTetromino *Tetromino) (tSpin TSpinType, clearLines int, perfectClear bool, ok bool) {
 	// 固定活跃方块
 	if f.active != nil {
-		if f.active.Type == T {
-			// 检查是否 T-Spin
-			corners := 0
-			for _, cornerLoc := range tCorners {
-				row := f.active.Row + cornerLoc.Row()
-				col := f.active.Column + cornerLoc.Column()
-				if row < 0 || col < 0 || col >= f.cols {
-					corners++
-					continue
-				}
-				if cell, _ := f.FilledTetromino(row, col); cell != TetrominoNone {
-					corners++
-					continue
-				}
-			}
-			if corners >= 3 {
-				tSpin = true
-			}
-		}
+		tSpin = f.TSpinType()
 		for _, cell := range f.active.Cells() {
 			_ = f.SetTetromino(cell.Row(), cell.Column(), f.active.Type)
 		}
@@ -231,9 +254,127 @@ func (f *Field) LockDown(newTetromino *Tetromino) (tSpin bool, clearLines int, o
 	for i := 0; i < clearLines; i++ {
 		f.filled = append(f.filled, make([]TetrominoType, f.cols))
 	}
+	perfectClear = clearLines > 0 && f.Empty()
 
 	// 更换活跃方块
-	return tSpin, clearLines, f.ChangeActiveTetromino(newTetromino)
+	return tSpin, clearLines, perfectClear, f.ChangeActiveTetromino(newTetromino)
+}
+
+// Empty 场上是否没有已填充的方块（不含活跃方块）
+func (f *Field) Empty() bool {
+	for _, row := range f.filled {
+		for _, cell := range row {
+			if cell != TetrominoNone {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TSpinType 根据活跃 T 方块四角占用情况判断 T-Spin 类型
+//
+// 四角中至少三个被占用时为 T-Spin ，其中凸起一侧两角均被占用为 TSpinFull ，否则为 TSpinMini 。活跃方块不是 T 或不使用 ShapesGuideline 形状集时返回 TSpinNone
+func (f *Field) TSpinType() TSpinType {
+	if f.active == nil || f.active.Type != T || f.active.Shapes != ShapesGuideline {
+		return TSpinNone
+	}
+	corners := 0
+	for _, cornerLoc := range tCorners {
+		if f.cornerOccupied(cornerLoc) {
+			corners++
+		}
+	}
+	if corners < 3 {
+		return TSpinNone
+	}
+	if f.active.Dir > DirL {
+		return TSpinMini
+	}
+	for _, cornerLoc := range tFrontCorners[f.active.Dir] {
+		if !f.cornerOccupied(cornerLoc) {
+			return TSpinMini
+		}
+	}
+	return TSpinFull
+}
+
+// cornerOccupied 活跃方块指定相对位置是否被占用（含边界）
+func (f *Field) cornerOccupied(loc Location) bool {
+	row := f.active.Row + loc.Row()
+	col := f.active.Column + loc.Column()
+	if row < 0 || col < 0 || col >= f.cols {
+		return true
+	}
+	cell, _ := f.FilledTetromino(row, col)
+	return cell != TetrominoNone
+}
+
+// AddGarbage 从底部加入垃圾行
+//
+// 加入 lines 行除 holeColumn 列外均填满的垃圾行，原有方块整体上移；若活跃方块因此与已填充方块重合，则将其上移直到不重合（最多上移 lines 行）。
+// 若有已填充方块被顶出场的上边界或活跃方块无法放置则返回 false （仍执行上移）。
+// holeColumn 不在场内时垃圾行没有空洞，调用方需自行检查
+func (f *Field) AddGarbage(lines, holeColumn int) bool {
+	if lines <= 0 {
+		return true
+	}
+	if lines > f.rows {
+		lines = f.rows
+	}
+
+	ok := true
+
+	// 检查是否有方块被顶出
+	for _, row := range f.filled[f.rows-lines:] {
+		for _, cell := range row {
+			if cell != TetrominoNone {
+				ok = false
+			}
+		}
+	}
+
+	// 上移并加入垃圾行
+	garbage := make([][]TetrominoType, lines)
+	for i := range garbage {
+		garbage[i] = make([]TetrominoType, f.cols)
+		for j := range garbage[i] {
+			if j != holeColumn {
+				garbage[i][j] = Garbage
+			}
+		}
+	}
+	f.filled = append(garbage, f.filled[:f.rows-lines]...)
+
+	// 上移活跃方块
+	if f.active != nil {
+		for i := 0; i < lines && !f.IsValid(); i++ {
+			f.active.Row++
+		}
+		if !f.IsValid() {
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// DropDistance 返回方块硬降可下落的行数
+func (f *Field) DropDistance(tetromino Tetromino) int {
+	distance := 0
+	for {
+		for _, cell := range tetromino.Cells() {
+			row := cell.Row() - distance - 1
+			col := cell.Column()
+			if row < 0 || col < 0 || col >= f.cols {
+				return distance
+			}
+			if filled, _ := f.FilledTetromino(row, col); filled != TetrominoNone {
+				return distance
+			}
+		}
+		distance++
+	}
 }
 
 // IsValid 是否合法
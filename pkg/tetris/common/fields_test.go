@@ -0,0 +1,239 @@
+package common_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// parseField 创建测试用的场
+//
+// rows 从上往下每行一个字符串， '.' 表示空格子，其余字符为方块类型名的首字母（ 'G' 表示垃圾行方块）
+func parseField(t *testing.T, rows []string, active *common.Tetromino) *common.Field {
+	t.Helper()
+	field := common.NewField(len(rows), len(rows[0]), nil)
+	for i, row := range rows {
+		for j := range row {
+			if row[j] == '.' {
+				continue
+			}
+			tetrominoType, ok := cellTypes[row[j]]
+			if !ok {
+				t.Fatalf("invalid cell %q at (%d, %d)", row[j], i, j)
+			}
+			field.SetTetromino(len(rows)-1-i, j, tetrominoType)
+		}
+	}
+	if active != nil && !field.ChangeActiveTetromino(active) {
+		t.Fatalf("invalid active tetromino: %+v", *active)
+	}
+	return field
+}
+
+// cellTypes 测试场中字符对应的方块类型
+var cellTypes = map[byte]common.TetrominoType{
+	'I': common.I, 'J': common.J, 'L': common.L, 'O': common.O,
+	'S': common.S, 'T': common.T, 'Z': common.Z, 'G': common.Garbage,
+}
+
+// fieldRows 返回场上已填充的方块，格式同 parseField
+func fieldRows(field common.FieldReader) []string {
+	rows, cols := field.Size()
+	ret := make([]string, rows)
+	for i := range ret {
+		row := make([]byte, cols)
+		for j := range row {
+			row[j] = '.'
+			if tetrominoType, _ := field.FilledTetromino(rows-1-i, j); tetrominoType != common.TetrominoNone {
+				row[j] = tetrominoType.String()[0]
+			}
+		}
+		ret[i] = string(row)
+	}
+	return ret
+}
+
+// TestFieldAddGarbage 测试从底部加入垃圾行
+func TestFieldAddGarbage(t *testing.T) {
+	cases := []struct {
+		name       string
+		rows       []string
+		active     *common.Tetromino
+		lines      int
+		holeColumn int
+		want       []string
+		wantRow    int
+		wantOK     bool
+	}{
+		{
+			name:       "empty",
+			rows:       []string{"....", "....", "....", "...."},
+			lines:      1,
+			holeColumn: 1,
+			want:       []string{"....", "....", "....", "G.GG"},
+			wantOK:     true,
+		},
+		{
+			name:       "shift-up",
+			rows:       []string{"....", "....", "....", "T..."},
+			lines:      2,
+			holeColumn: 2,
+			want:       []string{"....", "T...", "GG.G", "GG.G"},
+			wantOK:     true,
+		},
+		{
+			name:       "no-lines",
+			rows:       []string{"....", "....", "....", "T..."},
+			lines:      0,
+			holeColumn: 2,
+			want:       []string{"....", "....", "....", "T..."},
+			wantOK:     true,
+		},
+		{
+			name:       "top-out",
+			rows:       []string{"...I", "...I", "...I", "...I"},
+			lines:      1,
+			holeColumn: 0,
+			want:       []string{"...I", "...I", "...I", ".GGG"},
+			wantOK:     false,
+		},
+		{
+			name:       "push-active",
+			rows:       []string{"....", "....", "....", "...."},
+			active:     &common.Tetromino{Type: common.O, Row: 0, Column: 0},
+			lines:      1,
+			holeColumn: 3,
+			want:       []string{"....", "....", "....", "GGG."},
+			wantRow:    1,
+			wantOK:     true,
+		},
+		{
+			name:       "active-not-overlapped",
+			rows:       []string{"....", "....", "....", "...."},
+			active:     &common.Tetromino{Type: common.O, Row: 2, Column: 0},
+			lines:      1,
+			holeColumn: 3,
+			want:       []string{"....", "....", "....", "GGG."},
+			wantRow:    2,
+			wantOK:     true,
+		},
+		{
+			name:       "push-active-by-stack",
+			rows:       []string{"....", "....", "T...", "T..."},
+			active:     &common.Tetromino{Type: common.O, Row: 2, Column: 0},
+			lines:      1,
+			holeColumn: 3,
+			want:       []string{"....", "T...", "T...", "GGG."},
+			wantRow:    3,
+			wantOK:     true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var active *common.Tetromino
+			if c.active != nil {
+				tetromino := *c.active
+				active = &tetromino
+			}
+			field := parseField(t, c.rows, active)
+			if ok := field.AddGarbage(c.lines, c.holeColumn); ok != c.wantOK {
+				t.Errorf("result: %t, expected %t", ok, c.wantOK)
+			}
+			if got := fieldRows(field); !slices.Equal(got, c.want) {
+				t.Errorf("field:\n%v\nexpected:\n%v", got, c.want)
+			}
+			if c.active != nil {
+				if got := field.ActiveTetromino().Row; got != c.wantRow {
+					t.Errorf("active row: %d, expected %d", got, c.wantRow)
+				}
+			}
+		})
+	}
+}
+
+// TestFieldLockDown 测试锁定方块时的消行和 T-Spin 判定
+func TestFieldLockDown(t *testing.T) {
+	cases := []struct {
+		name      string
+		rows      []string
+		active    common.Tetromino
+		want      []string
+		wantTSpin common.TSpinType
+		wantLines int
+		wantPC    bool
+	}{
+		{
+			name:      "t-spin-double",
+			rows:      []string{"....", "G...", "...G", "G.GG"},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			want:      []string{"....", "....", "....", "G..."},
+			wantTSpin: common.TSpinFull,
+			wantLines: 2,
+		},
+		{
+			// 凸起一侧只有一个角被占用
+			name:      "t-spin-mini",
+			rows:      []string{"....", "....", "....", ".GGG"},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: -1, Dir: common.DirR},
+			want:      []string{"....", "....", "T...", "TT.."},
+			wantTSpin: common.TSpinMini,
+			wantLines: 1,
+		},
+		{
+			name:      "two-corners",
+			rows:      []string{"....", "....", "....", "...."},
+			active:    common.Tetromino{Type: common.T, Row: -1, Column: 0, Dir: common.Dir0},
+			want:      []string{"....", "....", ".T..", "TTT."},
+			wantTSpin: common.TSpinNone,
+		},
+		{
+			name:      "not-t",
+			rows:      []string{"....", "G...", "...G", "GG.G"},
+			active:    common.Tetromino{Type: common.J, Row: 0, Column: 0, Dir: common.Dir2},
+			want:      []string{"....", "....", "....", "G..."},
+			wantTSpin: common.TSpinNone,
+			wantLines: 2,
+		},
+		{
+			// 非标准形状集的 T 方块不判定 T-Spin
+			name:      "ars-t",
+			rows:      []string{"....", "G...", "...G", "G.GG"},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir0, Shapes: common.ShapesARS},
+			want:      []string{"....", "....", "....", "G..."},
+			wantTSpin: common.TSpinNone,
+			wantLines: 2,
+		},
+		{
+			name:      "perfect-clear",
+			rows:      []string{"....", "....", "...G", "G.GG"},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			want:      []string{"....", "....", "....", "...."},
+			wantTSpin: common.TSpinNone,
+			wantLines: 2,
+			wantPC:    true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			active := c.active
+			field := parseField(t, c.rows, &active)
+			tSpin, lines, perfectClear, ok := field.LockDown(nil)
+			if !ok {
+				t.Errorf("lock down failed")
+			}
+			if tSpin != c.wantTSpin {
+				t.Errorf("t-spin: %s, expected %s", tSpin, c.wantTSpin)
+			}
+			if lines != c.wantLines {
+				t.Errorf("clear lines: %d, expected %d", lines, c.wantLines)
+			}
+			if perfectClear != c.wantPC {
+				t.Errorf("perfect clear: %v, expected %v", perfectClear, c.wantPC)
+			}
+			if got := fieldRows(field); !slices.Equal(got, c.want) {
+				t.Errorf("field:\n%v\nexpected:\n%v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PieceDef 方块定义
+//
+// 可由 JSON 加载，用于定义七种标准方块以外的方块
+type PieceDef struct {
+	// 方块名，如 "I" 、 "P5" ，不可与已注册的方块重复
+	Name string `json:"name"`
+	// 颜色， CSS 颜色值，如 "#67c4ec"
+	Color string `json:"color,omitempty"`
+	// Dir0 DirR Dir2 DirL 四个方向下各格相对方块定位点的偏移 {row, col}
+	//
+	// 只指定 Dir0 时其它方向由 Dir0 在包含方块的最小正方形区域内顺时针旋转得到
+	Shapes [4][]Location `json:"shapes"`
+	// 踢墙表，键为起止方向如 "0R" 、 "R0" 、 "02" ，值为依次尝试的偏移 {row, col}
+	//
+	// 为空时由旋转系统决定踢墙方式
+	Kicks map[string][]Location `json:"kicks,omitempty"`
+}
+
+// Kick 返回从 from 方向旋转到 to 方向时的踢墙偏移，未定义时返回 nil
+func (def PieceDef) Kick(from, to TetrominoDir) []Location {
+	return def.Kicks[from.String()+to.String()]
+}
+
+// Complete 检查定义并补全未指定的方向
+func (def *PieceDef) Complete() error {
+	if def.Name == "" || strings.ContainsAny(def.Name, " .") {
+		return fmt.Errorf("invalid piece name: %q", def.Name)
+	}
+	if len(def.Shapes[Dir0]) == 0 {
+		return fmt.Errorf("piece %q has no cells", def.Name)
+	}
+	if len(def.Shapes[DirR]) == 0 && len(def.Shapes[Dir2]) == 0 && len(def.Shapes[DirL]) == 0 {
+		for dir := DirR; dir <= DirL; dir++ {
+			def.Shapes[dir] = rotateShape(def.Shapes[dir-1])
+		}
+	}
+	for dir, shape := range def.Shapes {
+		if len(shape) != len(def.Shapes[Dir0]) {
+			return fmt.Errorf("piece %q has %d cells in direction %s, expected %d",
+				def.Name, len(shape), TetrominoDir(dir), len(def.Shapes[Dir0]))
+		}
+	}
+	for key := range def.Kicks {
+		if !validKickKey(key) {
+			return fmt.Errorf("piece %q has invalid kick key: %q", def.Name, key)
+		}
+	}
+	return nil
+}
+
+// pieceDefs 已注册的方块定义，下标为 TetrominoType
+var pieceDefs = []PieceDef{
+	TetrominoNone: {Name: "None"},
+	I:             newBuiltinPieceDef("I", "#67c4ec", I),
+	J:             newBuiltinPieceDef("J", "#5f64a9", J),
+	L:             newBuiltinPieceDef("L", "#df8136", L),
+	O:             newBuiltinPieceDef("O", "#f0d543", O),
+	S:             newBuiltinPieceDef("S", "#62b451", S),
+	T:             newBuiltinPieceDef("T", "#a25399", T),
+	Z:             newBuiltinPieceDef("Z", "#db3e32", Z),
+	Garbage:       {Name: "Garbage", Color: "#6b6b6b"},
+}
+
+// RegisterPiece 注册方块定义，返回分配给该方块的类型
+//
+// 非线程安全，应在开始游戏前调用
+func RegisterPiece(def PieceDef) (TetrominoType, error) {
+	if err := def.Complete(); err != nil {
+		return TetrominoNone, err
+	}
+	if _, ok := LookupPiece(def.Name); ok {
+		return TetrominoNone, fmt.Errorf("piece %q already registered", def.Name)
+	}
+	if len(pieceDefs) > 255 {
+		return TetrominoNone, fmt.Errorf("too many pieces")
+	}
+	pieceDefs = append(pieceDefs, def)
+	return TetrominoType(len(pieceDefs) - 1), nil
+}
+
+// Piece 返回指定类型的方块定义
+func Piece(t TetrominoType) (PieceDef, bool) {
+	if int(t) >= len(pieceDefs) {
+		return PieceDef{}, false
+	}
+	return pieceDefs[t], true
+}
+
+// LookupPiece 根据方块名查找已注册的方块类型
+func LookupPiece(name string) (TetrominoType, bool) {
+	for i, def := range pieceDefs {
+		if def.Name == name {
+			return TetrominoType(i), true
+		}
+	}
+	return TetrominoNone, false
+}
+
+// IsPiece 是否为可作为活跃方块的方块类型
+func (t TetrominoType) IsPiece() bool {
+	return t != TetrominoNone && t != Garbage && int(t) < len(pieceDefs)
+}
+
+// newBuiltinPieceDef 根据标准形状创建内置方块定义
+func newBuiltinPieceDef(name, color string, t TetrominoType) PieceDef {
+	def := PieceDef{Name: name, Color: color}
+	for dir, shape := range tetrominoShapes[t-1] {
+		def.Shapes[dir] = shape[:]
+	}
+	return def
+}
+
+// rotateShape 将形状在包含它的最小正方形区域内顺时针旋转 90 度
+func rotateShape(shape []Location) []Location {
+	size := 0
+	for _, loc := range shape {
+		size = max(size, loc.Row()+1, loc.Column()+1)
+	}
+	ret := make([]Location, len(shape))
+	for i, loc := range shape {
+		ret[i] = Location{size - 1 - loc.Column(), loc.Row()}
+	}
+	return ret
+}
+
+// validKickKey 是否为合法的踢墙表键
+func validKickKey(key string) bool {
+	if len(key) != 2 {
+		return false
+	}
+	var from, to bool
+	for dir := Dir0; dir <= DirL; dir++ {
+		from = from || key[:1] == dir.String()
+		to = to || key[1:] == dir.String()
+	}
+	return from && to && key[0] != key[1]
+}
@@ -0,0 +1,111 @@
+package common_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// TestPieceDefComplete 测试方块定义的检查和补全
+func TestPieceDefComplete(t *testing.T) {
+	cases := []struct {
+		name    string
+		def     common.PieceDef
+		wantR   []common.Location
+		wantErr bool
+	}{
+		{
+			// L 形三格方块顺时针旋转
+			name:  "rotate",
+			def:   common.PieceDef{Name: "X", Shapes: [4][]common.Location{{{1, 0}, {0, 0}, {0, 1}}}},
+			wantR: []common.Location{{1, 1}, {1, 0}, {0, 0}},
+		},
+		{
+			name: "explicit",
+			def: common.PieceDef{Name: "X", Shapes: [4][]common.Location{
+				{{0, 0}}, {{1, 1}}, {{2, 2}}, {{3, 3}},
+			}},
+			wantR: []common.Location{{1, 1}},
+		},
+		{name: "no-name", def: common.PieceDef{Shapes: [4][]common.Location{{{0, 0}}}}, wantErr: true},
+		{name: "space-in-name", def: common.PieceDef{Name: "X Y", Shapes: [4][]common.Location{{{0, 0}}}}, wantErr: true},
+		{name: "no-cells", def: common.PieceDef{Name: "X"}, wantErr: true},
+		{
+			name:    "cells-mismatch",
+			def:     common.PieceDef{Name: "X", Shapes: [4][]common.Location{{{0, 0}}, {{0, 0}, {0, 1}}}},
+			wantErr: true,
+		},
+		{
+			name: "invalid-kick",
+			def: common.PieceDef{
+				Name:   "X",
+				Shapes: [4][]common.Location{{{0, 0}}},
+				Kicks:  map[string][]common.Location{"00": {{0, 0}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			def := c.def
+			err := def.Complete()
+			if (err != nil) != c.wantErr {
+				t.Fatalf("complete error: %v, expected error: %t", err, c.wantErr)
+			}
+			if err == nil && !slices.Equal(def.Shapes[common.DirR], c.wantR) {
+				t.Errorf("R shape: %v, expected %v", def.Shapes[common.DirR], c.wantR)
+			}
+		})
+	}
+}
+
+// TestRegisterPiece 测试注册方块
+func TestRegisterPiece(t *testing.T) {
+	def := common.PieceDef{
+		Name:   "TestBar",
+		Shapes: [4][]common.Location{{{0, 0}, {0, 1}, {0, 2}}},
+		Kicks:  map[string][]common.Location{"0R": {{0, 0}, {0, -1}}},
+	}
+	p, err := common.RegisterPiece(def)
+	if err != nil {
+		t.Fatalf("register piece error: %v", err)
+	}
+	if _, err := common.RegisterPiece(def); err == nil {
+		t.Errorf("expected duplicate piece error")
+	}
+	if _, err := common.RegisterPiece(common.PieceDef{Name: "T", Shapes: def.Shapes}); err == nil {
+		t.Errorf("expected builtin piece name error")
+	}
+
+	if !p.IsPiece() || p.String() != "TestBar" {
+		t.Errorf("registered piece: %s (is piece: %t)", p, p.IsPiece())
+	}
+	if got, ok := common.LookupPiece("TestBar"); !ok || got != p {
+		t.Errorf("lookup piece: %v %t, expected %v", got, ok, p)
+	}
+	if got, _ := common.Piece(p); !slices.Equal(got.Kick(common.Dir0, common.DirR), def.Kicks["0R"]) {
+		t.Errorf("kicks: %v, expected %v", got.Kick(common.Dir0, common.DirR), def.Kicks["0R"])
+	}
+	want := []common.Location{{3, 5}, {3, 6}, {3, 7}}
+	if got := (common.Tetromino{Type: p, Row: 3, Column: 5}).Cells(); !slices.Equal(got, want) {
+		t.Errorf("cells: %v, expected %v", got, want)
+	}
+
+	// 以方块名序列化
+	data, err := json.Marshal([]common.TetrominoType{common.T, p})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if string(data) != `["T","TestBar"]` {
+		t.Errorf("json: %s", data)
+	}
+	var types []common.TetrominoType
+	if err := json.Unmarshal(data, &types); err != nil || !slices.Equal(types, []common.TetrominoType{common.T, p}) {
+		t.Errorf("unmarshal: %v %v", types, err)
+	}
+	if common.Garbage.IsPiece() || common.TetrominoNone.IsPiece() {
+		t.Errorf("garbage or none is piece")
+	}
+}
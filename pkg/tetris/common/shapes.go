@@ -0,0 +1,155 @@
+package common
+
+import (
+	"fmt"
+)
+
+// ShapeSet 方块形状集
+//
+// 决定各类型方块在各方向下占用的格子，由旋转系统在创建方块时指定
+type ShapeSet byte
+
+// ShapeSet 的枚举
+const (
+	// ShapesGuideline 标准规则（ SRS ）的形状集，各方块以平的一面朝下出生， T 方块凸起朝上
+	ShapesGuideline ShapeSet = iota
+	// ShapesARS Arika 旋转系统（ ARS ）的形状集
+	//
+	// J L T 方块凸起朝下出生，各方向的方块贴着 3x3 区域的底部； I S Z 方块只有两个状态
+	ShapesARS
+	// ShapesNRS 任天堂旋转系统（ NRS ）的形状集
+	//
+	// 各方块绕固定的中心旋转，与 ARS 一样 J L T 方块凸起朝下出生； I S Z 方块只有两个状态
+	ShapesNRS
+)
+
+// shapeSets 已注册的形状集，下标为 ShapeSet
+var shapeSets = [][7][4][4]Location{
+	ShapesGuideline: tetrominoShapes,
+	ShapesARS:       arsShapes,
+	ShapesNRS:       nrsShapes,
+}
+
+// RegisterShapeSet 注册自定义的方块形状集
+//
+// shapes 依次为 I J L O S T Z 方块在 Dir0 DirR Dir2 DirL 方向下各格相对方块定位点的偏移。
+// 非线程安全，应在初始化阶段（如 init 函数中）调用
+func RegisterShapeSet(shapes [7][4][4]Location) (ShapeSet, error) {
+	if len(shapeSets) > 255 {
+		return 0, fmt.Errorf("too many shape sets")
+	}
+	shapeSets = append(shapeSets, shapes)
+	return ShapeSet(len(shapeSets) - 1), nil
+}
+
+var (
+	// arsShapes ARS 方块形状
+	//
+	// 参考 https://tetris.wiki/Arika_Rotation_System
+	arsShapes = [7][4][4]Location{
+		// I
+		{
+			{{2, 0}, {2, 1}, {2, 2}, {2, 3}},
+			{{0, 2}, {1, 2}, {2, 2}, {3, 2}},
+			{{2, 0}, {2, 1}, {2, 2}, {2, 3}},
+			{{0, 2}, {1, 2}, {2, 2}, {3, 2}},
+		},
+		// J
+		{
+			{{0, 2}, {1, 0}, {1, 1}, {1, 2}},
+			{{0, 0}, {0, 1}, {1, 1}, {2, 1}},
+			{{0, 0}, {0, 1}, {0, 2}, {1, 0}},
+			{{0, 1}, {1, 1}, {2, 1}, {2, 2}},
+		},
+		// L
+		{
+			{{0, 0}, {1, 0}, {1, 1}, {1, 2}},
+			{{0, 1}, {1, 1}, {2, 0}, {2, 1}},
+			{{0, 0}, {0, 1}, {0, 2}, {1, 2}},
+			{{0, 1}, {0, 2}, {1, 1}, {2, 1}},
+		},
+		// O
+		{
+			{{0, 1}, {0, 2}, {1, 1}, {1, 2}},
+			{{0, 1}, {0, 2}, {1, 1}, {1, 2}},
+			{{0, 1}, {0, 2}, {1, 1}, {1, 2}},
+			{{0, 1}, {0, 2}, {1, 1}, {1, 2}},
+		},
+		// S
+		{
+			{{0, 0}, {0, 1}, {1, 1}, {1, 2}},
+			{{0, 1}, {1, 0}, {1, 1}, {2, 0}},
+			{{0, 0}, {0, 1}, {1, 1}, {1, 2}},
+			{{0, 1}, {1, 0}, {1, 1}, {2, 0}},
+		},
+		// T
+		{
+			{{0, 1}, {1, 0}, {1, 1}, {1, 2}},
+			{{0, 1}, {1, 0}, {1, 1}, {2, 1}},
+			{{0, 0}, {0, 1}, {0, 2}, {1, 1}},
+			{{0, 1}, {1, 1}, {1, 2}, {2, 1}},
+		},
+		// Z
+		{
+			{{0, 1}, {0, 2}, {1, 0}, {1, 1}},
+			{{0, 1}, {1, 1}, {1, 2}, {2, 2}},
+			{{0, 1}, {0, 2}, {1, 0}, {1, 1}},
+			{{0, 1}, {1, 1}, {1, 2}, {2, 2}},
+		},
+	}
+
+	// nrsShapes NRS 方块形状（右手系，即 NES 版本）
+	//
+	// 参考 https://tetris.wiki/Nintendo_Rotation_System
+	nrsShapes = [7][4][4]Location{
+		// I
+		{
+			{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+			{{0, 2}, {1, 2}, {2, 2}, {3, 2}},
+			{{1, 0}, {1, 1}, {1, 2}, {1, 3}},
+			{{0, 2}, {1, 2}, {2, 2}, {3, 2}},
+		},
+		// J
+		{
+			{{0, 2}, {1, 0}, {1, 1}, {1, 2}},
+			{{0, 0}, {0, 1}, {1, 1}, {2, 1}},
+			{{1, 0}, {1, 1}, {1, 2}, {2, 0}},
+			{{0, 1}, {1, 1}, {2, 1}, {2, 2}},
+		},
+		// L
+		{
+			{{0, 0}, {1, 0}, {1, 1}, {1, 2}},
+			{{0, 1}, {1, 1}, {2, 0}, {2, 1}},
+			{{1, 0}, {1, 1}, {1, 2}, {2, 2}},
+			{{0, 1}, {0, 2}, {1, 1}, {2, 1}},
+		},
+		// O
+		{
+			{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+			{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+			{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+			{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+		},
+		// S
+		{
+			{{0, 0}, {0, 1}, {1, 1}, {1, 2}},
+			{{0, 2}, {1, 1}, {1, 2}, {2, 1}},
+			{{0, 0}, {0, 1}, {1, 1}, {1, 2}},
+			{{0, 2}, {1, 1}, {1, 2}, {2, 1}},
+		},
+		// T
+		{
+			{{0, 1}, {1, 0}, {1, 1}, {1, 2}},
+			{{0, 1}, {1, 0}, {1, 1}, {2, 1}},
+			{{1, 0}, {1, 1}, {1, 2}, {2, 1}},
+			{{0, 1}, {1, 1}, {1, 2}, {2, 1}},
+		},
+		// Z
+		{
+			{{0, 1}, {0, 2}, {1, 0}, {1, 1}},
+			{{0, 1}, {1, 1}, {1, 2}, {2, 2}},
+			{{0, 1}, {0, 2}, {1, 0}, {1, 1}},
+			{{0, 1}, {1, 1}, {1, 2}, {2, 2}},
+		},
+	}
+)
@@ -5,9 +5,11 @@ import (
 )
 
 // TetrominoType 方块类型
+//
+// 标准方块及垃圾行方块为内置类型，其它方块通过 RegisterPiece 注册后分配类型
 type TetrominoType byte
 
-// TetrominoType 的枚举
+// 内置的 TetrominoType
 const (
 	TetrominoNone TetrominoType = iota
 	I
@@ -17,31 +19,37 @@ const (
 	S
 	T
 	Z
+	// Garbage 垃圾行方块，不能作为活跃方块
+	Garbage
 )
 
 // String 返回字符串表示
+//
+// 已注册的方块返回方块名
 func (t TetrominoType) String() string {
-	switch t {
-	case TetrominoNone:
-		return "None"
-	case I:
-		return "I"
-	case J:
-		return "J"
-	case L:
-		return "L"
-	case O:
-		return "O"
-	case S:
-		return "S"
-	case T:
-		return "T"
-	case Z:
-		return "Z"
+	if def, ok := Piece(t); ok {
+		return def.Name
 	}
 	return fmt.Sprintf("Invalid(%d)", t)
 }
 
+// MarshalText 实现 encoding.TextMarshaler
+func (t TetrominoType) MarshalText() ([]byte, error) {
+	if _, ok := Piece(t); !ok {
+		return nil, fmt.Errorf("invalid tetromino type: %d", t)
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (t *TetrominoType) UnmarshalText(text []byte) error {
+	if ret, ok := LookupPiece(string(text)); ok {
+		*t = ret
+		return nil
+	}
+	return fmt.Errorf("invalid tetromino type: %q", text)
+}
+
 // TetrominoDir 方块方向
 type TetrominoDir byte
 
@@ -72,6 +80,32 @@ func (d TetrominoDir) String() string {
 	return fmt.Sprintf("Invalid(%d)", d)
 }
 
+// TSpinType T-Spin 类型
+type TSpinType byte
+
+// TSpinType 的枚举
+const (
+	// TSpinNone 非 T-Spin
+	TSpinNone TSpinType = iota
+	// TSpinMini T-Spin Mini
+	TSpinMini
+	// TSpinFull T-Spin
+	TSpinFull
+)
+
+// String 返回字符串表示
+func (t TSpinType) String() string {
+	switch t {
+	case TSpinNone:
+		return "None"
+	case TSpinMini:
+		return "Mini"
+	case TSpinFull:
+		return "Full"
+	}
+	return fmt.Sprintf("Invalid(%d)", t)
+}
+
 var (
 	// tetrominoShapes 方块形状
 	tetrominoShapes = [7][4][4]Location{
@@ -135,25 +169,37 @@ type Tetromino struct {
 	Row, Column int
 	// 方块方向
 	Dir TetrominoDir
+	// 方块形状集，默认为 ShapesGuideline
+	Shapes ShapeSet `json:",omitempty"`
 }
 
 // Cells 获取方块各格坐标
 //
-// 每个元素是一个方格的坐标
-func (t Tetromino) Cells() [4]Location {
-	// 获取相对方块定位点的偏移
-	if t.Type < 1 || t.Type > 7 || t.Dir < 0 || t.Dir > 3 {
-		return [4]Location{}
+// 每个元素是一个方格的坐标，每次调用返回新的切片
+func (t Tetromino) Cells() []Location {
+	shape := t.shape()
+	ret := make([]Location, len(shape))
+	for i, loc := range shape {
+		// 加上方块本身位置
+		ret[i] = Location{loc.Row() + t.Row, loc.Column() + t.Column}
 	}
-	ret := tetrominoShapes[t.Type-1][t.Dir]
+	return ret
+}
 
-	// 加上方块本身位置
-	for i := range ret {
-		ret[i][0] += t.Row
-		ret[i][1] += t.Column
+// shape 获取各格相对方块定位点的偏移
+//
+// 标准方块使用形状集 t.Shapes 中的形状，其它方块使用方块定义中的形状
+func (t Tetromino) shape() []Location {
+	if t.Dir > DirL {
+		return nil
 	}
-
-	return ret
+	if t.Type >= I && t.Type <= Z && int(t.Shapes) < len(shapeSets) {
+		return shapeSets[t.Shapes][t.Type-1][t.Dir][:]
+	}
+	if !t.Type.IsPiece() {
+		return nil
+	}
+	return pieceDefs[t.Type].Shapes[t.Dir]
 }
 
 // Location 格子位置 {row, col}
@@ -0,0 +1,153 @@
+package tetris_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+)
+
+// inputStep 输入 Op 后推进 Wait 个周期
+type inputStep struct {
+	Op   tetris.Op
+	Wait int
+}
+
+// newHandlingGame 创建用于测试按住按键的游戏
+//
+// 场宽 20 列，每秒 100 个周期， DAS 为 10 个周期，自然下落每 100 个周期一格
+func newHandlingGame(t *testing.T, arr time.Duration) tetris.Tetris {
+	t.Helper()
+	opts := tetris.DefaultOptions
+	opts.Seed = 1
+	opts.Columns = 20
+	opts.Frequency = 100
+	opts.DAS = 100 * time.Millisecond
+	opts.ARR = arr
+	opts.SoftDropFactor = 20
+	opts.SpeedController = func(int) float64 { return 1 }
+	return newManualGame(t, opts)
+}
+
+// TestAutoShift 测试按住移动键时按 DAS 、 ARR 自动重复移动
+func TestAutoShift(t *testing.T) {
+	cases := []struct {
+		name  string
+		arr   time.Duration
+		steps []inputStep
+		// 方块的列变化， toWall 表示移动到左侧墙边
+		want   int
+		toWall bool
+	}{
+		{name: "tap", arr: 20 * time.Millisecond, steps: []inputStep{{tetris.OpMoveLeftPress, 0}}, want: -1},
+		{name: "before-das", arr: 20 * time.Millisecond, steps: []inputStep{{tetris.OpMoveLeftPress, 9}}, want: -1},
+		{name: "das", arr: 20 * time.Millisecond, steps: []inputStep{{tetris.OpMoveLeftPress, 10}}, want: -2},
+		{name: "arr", arr: 20 * time.Millisecond, steps: []inputStep{{tetris.OpMoveLeftPress, 14}}, want: -4},
+		{name: "arr-zero", arr: 0, steps: []inputStep{{tetris.OpMoveLeftPress, 10}}, toWall: true},
+		{
+			name:  "release-before-das",
+			arr:   20 * time.Millisecond,
+			steps: []inputStep{{tetris.OpMoveLeftPress, 5}, {tetris.OpMoveLeftRelease, 20}},
+			want:  -1,
+		},
+		{
+			name: "release-after-das",
+			arr:  20 * time.Millisecond,
+			steps: []inputStep{
+				{tetris.OpMoveRightPress, 12},
+				{tetris.OpMoveRightRelease, 20},
+			},
+			want: 3,
+		},
+		{
+			name: "last-pressed-wins",
+			arr:  20 * time.Millisecond,
+			steps: []inputStep{
+				{tetris.OpMoveLeftPress, 5},
+				{tetris.OpMoveRightPress, 10},
+			},
+			want: 1,
+		},
+		{
+			name: "back-to-held-direction",
+			arr:  20 * time.Millisecond,
+			steps: []inputStep{
+				{tetris.OpMoveLeftPress, 5},
+				{tetris.OpMoveRightPress, 5},
+				{tetris.OpMoveRightRelease, 10},
+			},
+			want: -1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			game := newHandlingGame(t, c.arr)
+			before := *game.CurrentFrame().Field.ActiveTetromino()
+			for _, step := range c.steps {
+				game.Input(step.Op)
+				if err := game.Step(step.Wait); err != nil {
+					t.Fatalf("step error: %v", err)
+				}
+			}
+			after := *game.CurrentFrame().Field.ActiveTetromino()
+			if after.Row != before.Row {
+				t.Fatalf("active tetromino fell: row %d -> %d", before.Row, after.Row)
+			}
+			if c.toWall {
+				game.Input(tetris.OpMoveLeft)
+				if moved := *game.CurrentFrame().Field.ActiveTetromino(); moved.Column != after.Column {
+					t.Errorf("not at the wall: column %d, can move to %d", after.Column, moved.Column)
+				}
+				return
+			}
+			if got := after.Column - before.Column; got != c.want {
+				t.Errorf("column delta: %d, expected %d", got, c.want)
+			}
+		})
+	}
+}
+
+// TestSoftDropHeld 测试按住软下落键时按 SoftDropFactor 倍速下落
+func TestSoftDropHeld(t *testing.T) {
+	cases := []struct {
+		name  string
+		steps []inputStep
+		// 输入后暂停并继续游戏，再推进 50 个周期
+		pause bool
+		want  int
+	}{
+		{name: "press", steps: []inputStep{{tetris.OpSoftDropPress, 0}}, want: 1},
+		// 按住时每 6 个周期下落一格
+		{name: "held", steps: []inputStep{{tetris.OpSoftDropPress, 12}}, want: 3},
+		{name: "released", steps: []inputStep{{tetris.OpSoftDropPress, 6}, {tetris.OpSoftDropRelease, 50}}, want: 2},
+		{name: "paused", steps: []inputStep{{tetris.OpSoftDropPress, 0}}, pause: true, want: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			game := newHandlingGame(t, 0)
+			before := *game.CurrentFrame().Field.ActiveTetromino()
+			for _, step := range c.steps {
+				game.Input(step.Op)
+				if err := game.Step(step.Wait); err != nil {
+					t.Fatalf("step error: %v", err)
+				}
+			}
+			if c.pause {
+				// 暂停时松开所有按键
+				if err := game.Pause(); err != nil {
+					t.Fatalf("pause error: %v", err)
+				}
+				if err := game.Resume(); err != nil {
+					t.Fatalf("resume error: %v", err)
+				}
+				if err := game.Step(50); err != nil {
+					t.Fatalf("step error: %v", err)
+				}
+			}
+			after := *game.CurrentFrame().Field.ActiveTetromino()
+			if got := before.Row - after.Row; got != c.want {
+				t.Errorf("dropped rows: %d, expected %d", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package tetris
+
+import (
+	"fmt"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// EventType 事件类型
+type EventType byte
+
+// EventType 的枚举值
+const (
+	// EventScore 得分
+	EventScore EventType = iota
+	// EventLockDown 方块锁定
+	EventLockDown
+	// EventLineClear 消行
+	EventLineClear
+	// EventTSpin T-Spin
+	EventTSpin
+	// EventHold 暂存方块
+	EventHold
+	// EventLevelUp 升级
+	EventLevelUp
+	// EventGameOver 游戏结束
+	EventGameOver
+	// EventPerfectClear 消行后场上没有任何方块
+	EventPerfectClear
+)
+
+// String 返回字符串表示
+func (t EventType) String() string {
+	switch t {
+	case EventScore:
+		return "Score"
+	case EventLockDown:
+		return "LockDown"
+	case EventLineClear:
+		return "LineClear"
+	case EventTSpin:
+		return "TSpin"
+	case EventHold:
+		return "Hold"
+	case EventLevelUp:
+		return "LevelUp"
+	case EventGameOver:
+		return "GameOver"
+	case EventPerfectClear:
+		return "PerfectClear"
+	}
+	return fmt.Sprintf("Invalid(%d)", t)
+}
+
+// Event 游戏事件
+//
+// 不同类型的事件使用的字段不同，未使用的字段为零值
+type Event struct {
+	// 事件类型
+	Type EventType
+	// 事件发生时所在周期
+	Ticket int64
+
+	// 相关方块
+	//
+	// EventLockDown 、 EventLineClear 、 EventTSpin 、 EventPerfectClear 为被锁定的方块（含位置和方向）， EventHold 为被暂存的方块
+	Tetromino common.Tetromino
+	// 清除行数
+	ClearLines int
+	// T-Spin 类型
+	TSpin common.TSpinType
+	// 是否全消
+	PerfectClear bool
+	// 连击数
+	Combo int
+	// 得分增量
+	Score int
+	// 得分原因
+	Reason []string
+	// 当前级别
+	Level int
+	// 被锁定方块的 Finesse 错误数（实际操作数比最少操作数多出的部分），仅 EventLockDown 使用
+	FinesseFaults int
+	// 游戏结果，仅 EventGameOver 使用
+	Result GameResult
+	// 导致游戏失败的顶出类型，仅 EventGameOver 使用
+	TopOut TopOutType
+}
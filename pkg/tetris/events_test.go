@@ -0,0 +1,101 @@
+package tetris_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+)
+
+// drainEventTypes 取出通道中已有的事件的类型
+func drainEventTypes(events <-chan tetris.Event) []tetris.EventType {
+	var ret []tetris.EventType
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return ret
+			}
+			ret = append(ret, e.Type)
+		default:
+			return ret
+		}
+	}
+}
+
+// TestEvents 测试操作产生的事件
+func TestEvents(t *testing.T) {
+	cases := []struct {
+		name string
+		ops  []tetris.Op
+		want []tetris.EventType
+	}{
+		{
+			name: "hard-drop",
+			ops:  []tetris.Op{tetris.OpHardDrop},
+			want: []tetris.EventType{tetris.EventScore, tetris.EventLockDown},
+		},
+		{name: "hold", ops: []tetris.Op{tetris.OpHold}, want: []tetris.EventType{tetris.EventHold}},
+		{name: "hold-twice", ops: []tetris.Op{tetris.OpHold, tetris.OpHold}, want: []tetris.EventType{tetris.EventHold}},
+		{
+			name: "hold-after-lock-down",
+			ops:  []tetris.Op{tetris.OpHold, tetris.OpHardDrop, tetris.OpHold},
+			want: []tetris.EventType{tetris.EventHold, tetris.EventScore, tetris.EventLockDown, tetris.EventHold},
+		},
+		{name: "move", ops: []tetris.Op{tetris.OpMoveLeft, tetris.OpRotateRight}, want: nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			game := newManualGame(t, opts)
+			events := game.Events()
+			for _, op := range c.ops {
+				game.Input(op)
+			}
+			if got := drainEventTypes(events); !slices.Equal(got, c.want) {
+				t.Errorf("events: %v, expected %v", got, c.want)
+			}
+		})
+	}
+}
+
+// TestLockDownEvent 测试锁定事件包含被锁定的方块
+func TestLockDownEvent(t *testing.T) {
+	opts := tetris.DefaultOptions
+	opts.Seed = 1
+	game := newManualGame(t, opts)
+	events := game.Events()
+	active := *game.CurrentFrame().Field.ActiveTetromino()
+	game.Input(tetris.OpHardDrop)
+
+	// 硬下落得分事件在锁定事件之前
+	if e := <-events; e.Type != tetris.EventScore || e.Score <= 0 {
+		t.Fatalf("first event: %+v, expected %s with positive score", e, tetris.EventScore)
+	}
+	e := <-events
+	if e.Type != tetris.EventLockDown {
+		t.Fatalf("event type: %s, expected %s", e.Type, tetris.EventLockDown)
+	}
+	if e.Tetromino.Type != active.Type || e.Tetromino.Column != active.Column || e.Tetromino.Row >= active.Row {
+		t.Errorf("locked tetromino: %+v, expected %s dropped from %+v", e.Tetromino, active.Type, active)
+	}
+}
+
+// TestGameOverEvent 测试停止游戏时发送游戏结束事件并关闭事件通道
+func TestGameOverEvent(t *testing.T) {
+	opts := tetris.DefaultOptions
+	opts.Seed = 1
+	game := newManualGame(t, opts)
+	events := game.Events()
+	if err := game.Stop(); err != nil {
+		t.Fatalf("stop game error: %v", err)
+	}
+	want := []tetris.EventType{tetris.EventGameOver}
+	if got := drainEventTypes(events); !slices.Equal(got, want) {
+		t.Errorf("events: %v, expected %v", got, want)
+	}
+	if _, ok := <-events; ok {
+		t.Errorf("events channel not closed")
+	}
+}
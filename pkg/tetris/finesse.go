@@ -0,0 +1,111 @@
+package tetris
+
+import (
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/movegen"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// minimalInputs 计算将方块从出生位置 spawn 移动到锁定位置 target 所需的最少操作数
+//
+// 左右移动一格、按住左右移动到底（ DAS ）、旋转、软降到底各计一次操作，硬降不计入。
+// 占用格子相同的位置视为同一位置（如 O 方块的各个方向）。无法到达时返回 -1
+func minimalInputs(
+	field *common.BitField,
+	rs rotationsystems.RotationSystem,
+	spawn, target common.Tetromino,
+) int {
+	minimal := -1
+	for _, placement := range movegen.Generate(field, rs, spawn, movegen.AllMoves) {
+		if movegen.SameCells(placement.Tetromino, target) && (minimal < 0 || len(placement.Path) < minimal) {
+			minimal = len(placement.Path)
+		}
+	}
+	return minimal
+}
+
+// countPieceInput 记录当前方块使用的操作数，用于分析 Finesse 错误
+//
+// 连续的软降只计一次操作，松开按键、硬降、暂存不计入
+func (t *defaultTetris) countPieceInput(op Op) {
+	switch op {
+	case OpSoftDrop, OpSoftDropPress:
+		if !t.pieceSoftDropping {
+			t.pieceInputs++
+		}
+		t.pieceSoftDropping = true
+	case OpMoveRight, OpMoveLeft, OpMoveRightPress, OpMoveLeftPress, OpRotateRight, OpRotateLeft, OpRotate180:
+		t.pieceInputs++
+		t.pieceSoftDropping = false
+	default:
+	}
+}
+
+// finesseKey 空场中最少操作数缓存的键
+type finesseKey struct {
+	Type   common.TetrominoType
+	Dir    common.TetrominoDir
+	Column int
+}
+
+// finesseFaults 计算即将锁定的活跃方块的 Finesse 错误数，即实际操作数比最少操作数多出的部分
+//
+// 方块可从上方直接落到锁定位置时使用空场中的最少操作数（按方块类型、方向、列缓存），否则在当前场中搜索
+func (t *defaultTetris) finesseFaults() int {
+	active := t.field.ActiveTetromino()
+	if !t.finesse || active == nil {
+		return 0
+	}
+	spawn, ok := SpawnTetromino(t.field, t.rotationSystem, active.Type, t.rows)
+	if !ok {
+		return 0
+	}
+	var minimal int
+	if t.clearAbove(*active, spawn.Row) {
+		minimal = t.emptyFieldMinimalInputs(*active)
+	} else {
+		minimal = minimalInputs(t.field, t.rotationSystem, spawn, *active)
+	}
+	if minimal < 0 {
+		// 无法从出生位置到达（如期间加入了垃圾行）
+		return 0
+	}
+	return max(t.pieceInputs-minimal, 0)
+}
+
+// clearAbove 方块所在位置上方直到出生位置所在行是否都没有已填充的格子
+func (t *defaultTetris) clearAbove(target common.Tetromino, spawnRow int) bool {
+	for row := target.Row + 1; row <= spawnRow; row++ {
+		target.Row = row
+		for _, cell := range target.Cells() {
+			if filled, _ := t.field.FilledTetromino(cell.Row(), cell.Column()); filled != common.TetrominoNone {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// emptyFieldMinimalInputs 返回空场中将方块从出生位置移动到 target 所在方向、列并落到底所需的最少操作数，无法到达时返回 -1
+func (t *defaultTetris) emptyFieldMinimalInputs(target common.Tetromino) int {
+	key := finesseKey{Type: target.Type, Dir: target.Dir, Column: target.Column}
+	if minimal, ok := t.finesseMinimalInputs[key]; ok {
+		return minimal
+	}
+	rows, cols := t.field.Size()
+	field := common.NewBitField(rows, cols, &target)
+	for field.MoveActiveTetromino(-1, 0) {
+	}
+	minimal := -1
+	if spawn, ok := SpawnTetromino(field, t.rotationSystem, target.Type, t.rows); ok {
+		minimal = minimalInputs(field, t.rotationSystem, spawn, *field.ActiveTetromino())
+	}
+	t.finesseMinimalInputs[key] = minimal
+	return minimal
+}
+
+// resetPieceInputs 重置当前方块使用的操作数
+func (t *defaultTetris) resetPieceInputs() {
+	t.pieceInputs = 0
+	t.pieceSoftDropping = false
+}
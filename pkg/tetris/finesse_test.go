@@ -0,0 +1,86 @@
+package tetris_test
+
+import (
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// TestFinesseFaults 测试锁定方块时的 Finesse 错误数
+func TestFinesseFaults(t *testing.T) {
+	cases := []struct {
+		name       string
+		disabled   bool
+		ops        []tetris.Op
+		wantFaults int
+	}{
+		{name: "hard-drop", ops: []tetris.Op{tetris.OpHardDrop}},
+		{name: "move", ops: []tetris.Op{tetris.OpMoveLeft, tetris.OpHardDrop}},
+		{
+			name:       "move-back-and-forth",
+			ops:        []tetris.Op{tetris.OpMoveLeft, tetris.OpMoveRight, tetris.OpMoveLeft, tetris.OpHardDrop},
+			wantFaults: 2,
+		},
+		{
+			name: "full-rotation",
+			ops: []tetris.Op{
+				tetris.OpRotateRight, tetris.OpRotateRight, tetris.OpRotateRight, tetris.OpRotateRight,
+				tetris.OpHardDrop,
+			},
+			wantFaults: 4,
+		},
+		{
+			// 使用 180 度旋转比两次 90 度旋转少一次操作
+			name:       "two-rotations",
+			ops:        []tetris.Op{tetris.OpRotateRight, tetris.OpRotateRight, tetris.OpHardDrop},
+			wantFaults: 1,
+		},
+		{
+			// 连续的软降只计一次操作
+			name:       "soft-drop",
+			ops:        []tetris.Op{tetris.OpSoftDrop, tetris.OpSoftDrop, tetris.OpSoftDrop, tetris.OpHardDrop},
+			wantFaults: 1,
+		},
+		{
+			name:     "disabled",
+			disabled: true,
+			ops:      []tetris.Op{tetris.OpMoveLeft, tetris.OpMoveRight, tetris.OpMoveLeft, tetris.OpHardDrop},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.FinesseEnabled = !c.disabled
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			snapshot.ActiveTetromino.Type = common.T
+			game := restoreManualGame(t, snapshot)
+			events := game.Events()
+			for _, op := range c.ops {
+				game.Input(op)
+			}
+
+			var lockDown *tetris.Event
+			for lockDown == nil {
+				select {
+				case e := <-events:
+					if e.Type == tetris.EventLockDown {
+						lockDown = &e
+					}
+				default:
+					t.Fatalf("no lock down event")
+				}
+			}
+			if lockDown.FinesseFaults != c.wantFaults {
+				t.Errorf("event finesse faults: %d, expected %d", lockDown.FinesseFaults, c.wantFaults)
+			}
+			if got := game.CurrentFrame().Stats.FinesseFaults; got != c.wantFaults {
+				t.Errorf("stats finesse faults: %d, expected %d", got, c.wantFaults)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package tetris_test
+
+import (
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// TestAddGarbage 测试加入垃圾行的参数检查和顶出
+func TestAddGarbage(t *testing.T) {
+	cases := []struct {
+		name       string
+		lines      int
+		holeColumn int
+		times      int
+		wantErr    bool
+		wantResult tetris.GameResult
+		wantTopOut tetris.TopOutType
+	}{
+		{name: "one-line", lines: 1, holeColumn: 0, times: 1},
+		{name: "no-lines", lines: 0, holeColumn: 9, times: 1},
+		{name: "negative-lines", lines: -1, holeColumn: 0, times: 1, wantErr: true},
+		{name: "negative-hole", lines: 1, holeColumn: -1, times: 1, wantErr: true},
+		{name: "hole-out-of-field", lines: 1, holeColumn: 10, times: 1, wantErr: true},
+		// 场共 40 行（含 20 行隐藏行）
+		{name: "top-out", lines: 4, holeColumn: 3, times: 11, wantResult: tetris.ResultLost, wantTopOut: tetris.TopOutGarbage},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := tetris.NewRecorder()
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.Recorder = recorder
+			game := newManualGame(t, opts)
+			var err error
+			for i := 0; i < c.times && err == nil && game.State() == tetris.StateRunning; i++ {
+				err = game.AddGarbage(c.lines, c.holeColumn)
+			}
+			if (err != nil) != c.wantErr {
+				t.Fatalf("add garbage error: %v, expected error: %t", err, c.wantErr)
+			}
+			if c.wantErr && len(recorder.Replay().Inputs) != 0 {
+				// 参数错误时不记录
+				t.Errorf("invalid garbage recorded: %+v", recorder.Replay().Inputs)
+			}
+
+			frame := game.CurrentFrame()
+			if frame.Result != c.wantResult {
+				t.Errorf("result: %s, expected %s", frame.Result, c.wantResult)
+			}
+			if frame.TopOut != c.wantTopOut {
+				t.Errorf("top out: %s, expected %s", frame.TopOut, c.wantTopOut)
+			}
+			if c.wantErr || c.wantResult != tetris.ResultNone {
+				return
+			}
+			rows, cols := frame.Field.Size()
+			for row := 0; row < rows; row++ {
+				filled := 0
+				for col := 0; col < cols; col++ {
+					if tetrominoType, _ := frame.Field.FilledTetromino(row, col); tetrominoType != common.TetrominoNone {
+						filled++
+					}
+				}
+				want := 0
+				if row < c.lines {
+					want = cols - 1
+				}
+				if filled != want {
+					t.Errorf("row %d: %d cells filled, expected %d", row, filled, want)
+				}
+			}
+		})
+	}
+
+	// 游戏结束后不能加入垃圾行
+	opts := tetris.DefaultOptions
+	opts.Seed = 1
+	game := newManualGame(t, opts)
+	if err := game.Stop(); err != nil {
+		t.Fatalf("stop game error: %v", err)
+	}
+	if err := game.AddGarbage(1, 0); err == nil {
+		t.Errorf("add garbage after stopped: no error, expected error")
+	}
+}
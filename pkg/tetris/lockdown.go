@@ -0,0 +1,106 @@
+package tetris
+
+import (
+	"fmt"
+	"time"
+)
+
+// LockDownPolicy 锁定策略，决定方块着地后的锁定延迟如何重置
+//
+// 参考 https://tetris.wiki/Lock_delay
+type LockDownPolicy byte
+
+// LockDownPolicy 的枚举值
+const (
+	// LockDownDefault 未指定锁定策略，由已弃用的 Options.LockDownReset 决定
+	LockDownDefault LockDownPolicy = iota
+	// LockDownMoveReset 移动重置（准则中的 Extended Placement ）
+	//
+	// 移动、旋转成功时重置锁定延迟，每个方块最多重置 Options.LockDelayMaxResetTimes 次，方块下落到新的最低行时重置次数清零
+	LockDownMoveReset
+	// LockDownStepReset 下落重置
+	//
+	// 只有方块下落到新的最低行时才重置锁定延迟，移动、旋转不重置
+	LockDownStepReset
+	// LockDownInfinite 无限重置（准则中的 Infinite Placement ）
+	//
+	// 移动、旋转成功时总是重置锁定延迟
+	LockDownInfinite
+	// LockDownClassic 经典锁定
+	//
+	// 没有锁定延迟，方块自然下落或软下落受阻时立即锁定
+	LockDownClassic
+)
+
+// String 返回字符串表示
+func (p LockDownPolicy) String() string {
+	switch p {
+	case LockDownDefault:
+		return "Default"
+	case LockDownMoveReset:
+		return "MoveReset"
+	case LockDownStepReset:
+		return "StepReset"
+	case LockDownInfinite:
+		return "Infinite"
+	case LockDownClassic:
+		return "Classic"
+	}
+	return fmt.Sprintf("Invalid(%d)", p)
+}
+
+// grounded 活跃方块是否已着地
+func (t *defaultTetris) grounded() bool {
+	if ok := t.field.MoveActiveTetromino(-1, 0); ok {
+		t.field.MoveActiveTetromino(1, 0)
+		return false
+	}
+	return true
+}
+
+// resetLockDownDelay 移动、旋转后按锁定策略重置锁定延迟计数器
+func (t *defaultTetris) resetLockDownDelay() {
+	switch t.lockDownPolicy {
+	case LockDownMoveReset:
+		if t.lockDelayMaxResetTimes > 0 && t.lockDownResetTimes >= t.lockDelayMaxResetTimes {
+			return
+		}
+		t.lockDownTickets = 0
+		t.lockDownResetTimes++
+	case LockDownInfinite:
+		t.lockDownTickets = 0
+	}
+}
+
+// resetLockDownOnDrop 方块下落一格后按锁定策略重置锁定延迟计数器
+func (t *defaultTetris) resetLockDownOnDrop() {
+	row := t.field.ActiveTetromino().Row
+	lowest := row < t.lowestRow
+	if lowest {
+		t.lowestRow = row
+		t.lockDownResetTimes = 0
+	}
+	if lowest || t.lockDownPolicy != LockDownStepReset {
+		t.lockDownTickets = 0
+	}
+}
+
+// fullyResetLockDown 新方块出生后完全重置锁定延迟相关计数器
+func (t *defaultTetris) fullyResetLockDown() {
+	t.lockDownTickets = 0
+	t.lockDownResetTimes = 0
+	t.lowestRow = t.field.ActiveTetromino().Row
+}
+
+// currentLockDelay 返回实际使用的锁定延迟
+func (t *defaultTetris) currentLockDelay() time.Duration {
+	if t.lockDownPolicy == LockDownClassic {
+		return 0
+	}
+	return t.lockDelay
+}
+
+// lockDelayRemaining 返回活跃方块锁定前剩余的锁定延迟
+func (t *defaultTetris) lockDelayRemaining() time.Duration {
+	return max(t.currentLockDelay()-time.Duration(t.lockDownTickets)*time.Second/time.Duration(t.freq), 0)
+}
@@ -0,0 +1,251 @@
+package tetris_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// lockDownStep 锁定测试中的一步，先经过若干周期再依次输入操作
+type lockDownStep struct {
+	wait int
+	ops  []tetris.Op
+}
+
+// TestLockDownPolicy 测试各锁定策略下锁定延迟的重置
+func TestLockDownPolicy(t *testing.T) {
+	// 着地的 O 方块，每周期 1ms ，锁定延迟 500ms
+	grounded := common.Tetromino{Type: common.O, Row: 0, Column: 4}
+	cases := []struct {
+		name          string
+		policy        tetris.LockDownPolicy
+		maxResetTimes int
+		rows          []string
+		active        common.Tetromino
+		steps         []lockDownStep
+		wantLocked    bool
+	}{
+		{
+			name:          "no-input-before-delay",
+			policy:        tetris.LockDownMoveReset,
+			maxResetTimes: 15,
+			active:        grounded,
+			steps:         []lockDownStep{{wait: 500}},
+		},
+		{
+			name:          "no-input-after-delay",
+			policy:        tetris.LockDownMoveReset,
+			maxResetTimes: 15,
+			active:        grounded,
+			steps:         []lockDownStep{{wait: 501}},
+			wantLocked:    true,
+		},
+		{
+			name:          "move-reset",
+			policy:        tetris.LockDownMoveReset,
+			maxResetTimes: 15,
+			active:        grounded,
+			steps:         []lockDownStep{{wait: 400, ops: []tetris.Op{tetris.OpMoveLeft}}, {wait: 400}},
+		},
+		{
+			// 第三次移动时已用完重置次数
+			name:          "move-reset-max-times",
+			policy:        tetris.LockDownMoveReset,
+			maxResetTimes: 2,
+			active:        grounded,
+			steps: []lockDownStep{
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveLeft}},
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveRight}},
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveLeft}},
+				{wait: 101},
+			},
+			wantLocked: true,
+		},
+		{
+			// 最大重置次数为 0 时可无限重置
+			name:          "move-reset-unlimited",
+			policy:        tetris.LockDownMoveReset,
+			maxResetTimes: 0,
+			active:        grounded,
+			steps: slices.Repeat([]lockDownStep{
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveLeft}},
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveRight}},
+			}, 20),
+		},
+		{
+			name:          "step-reset-move",
+			policy:        tetris.LockDownStepReset,
+			maxResetTimes: 15,
+			active:        grounded,
+			steps:         []lockDownStep{{wait: 400, ops: []tetris.Op{tetris.OpMoveLeft}}, {wait: 101}},
+			wantLocked:    true,
+		},
+		{
+			// 移出平台后下落到新的最低行时重置
+			name:          "step-reset-lowest-row",
+			policy:        tetris.LockDownStepReset,
+			maxResetTimes: 15,
+			rows:          []string{"GG........"},
+			active:        common.Tetromino{Type: common.O, Row: 1, Column: 0},
+			steps: []lockDownStep{
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveRight, tetris.OpMoveRight, tetris.OpSoftDrop}},
+				{wait: 450},
+			},
+		},
+		{
+			name:          "infinite",
+			policy:        tetris.LockDownInfinite,
+			maxResetTimes: 2,
+			active:        grounded,
+			steps: slices.Repeat([]lockDownStep{
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveLeft}},
+				{wait: 400, ops: []tetris.Op{tetris.OpMoveRight}},
+			}, 20),
+		},
+		{
+			name:   "classic-no-delay",
+			policy: tetris.LockDownClassic,
+			active: grounded,
+			steps:  []lockDownStep{{wait: 1, ops: []tetris.Op{tetris.OpMoveLeft}}, {wait: 600}},
+		},
+		{
+			// 软下落受阻时立即锁定
+			name:       "classic-soft-drop",
+			policy:     tetris.LockDownClassic,
+			active:     grounded,
+			steps:      []lockDownStep{{wait: 1, ops: []tetris.Op{tetris.OpSoftDrop}}},
+			wantLocked: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.Frequency = 1000
+			opts.LockDownPolicy = c.policy
+			opts.LockDelayMaxResetTimes = c.maxResetTimes
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			setSnapshotField(&snapshot, c.rows...)
+			snapshot.ActiveTetromino = c.active
+			game := restoreManualGame(t, snapshot)
+			events := game.Events()
+			for _, step := range c.steps {
+				if err := game.Step(step.wait); err != nil {
+					t.Fatalf("step error: %v", err)
+				}
+				for _, op := range step.ops {
+					game.Input(op)
+				}
+			}
+
+			locked := slices.Contains(drainEventTypes(events), tetris.EventLockDown)
+			if locked != c.wantLocked {
+				t.Errorf("locked: %t, expected %t", locked, c.wantLocked)
+			}
+		})
+	}
+}
+
+// TestLockDelayRemaining 测试帧中的剩余锁定延迟
+func TestLockDelayRemaining(t *testing.T) {
+	cases := []struct {
+		name          string
+		policy        tetris.LockDownPolicy
+		active        common.Tetromino
+		wait          int
+		wantDelay     time.Duration
+		wantRemaining time.Duration
+	}{
+		{
+			name:          "grounded",
+			policy:        tetris.LockDownMoveReset,
+			active:        common.Tetromino{Type: common.O, Row: 0, Column: 4},
+			wait:          100,
+			wantDelay:     500 * time.Millisecond,
+			wantRemaining: 400 * time.Millisecond,
+		},
+		{
+			// 未着地时不计时
+			name:          "falling",
+			policy:        tetris.LockDownMoveReset,
+			active:        common.Tetromino{Type: common.O, Row: 10, Column: 4},
+			wait:          100,
+			wantDelay:     500 * time.Millisecond,
+			wantRemaining: 500 * time.Millisecond,
+		},
+		{
+			name:   "classic",
+			policy: tetris.LockDownClassic,
+			active: common.Tetromino{Type: common.O, Row: 10, Column: 4},
+			wait:   100,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.Frequency = 1000
+			opts.LockDownPolicy = c.policy
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			snapshot.ActiveTetromino = c.active
+			game := restoreManualGame(t, snapshot)
+			if err := game.Step(c.wait); err != nil {
+				t.Fatalf("step error: %v", err)
+			}
+			frame := game.CurrentFrame()
+			if frame.LockDelay != c.wantDelay {
+				t.Errorf("lock delay: %s, expected %s", frame.LockDelay, c.wantDelay)
+			}
+			if frame.LockDelayRemaining != c.wantRemaining {
+				t.Errorf("lock delay remaining: %s, expected %s", frame.LockDelayRemaining, c.wantRemaining)
+			}
+		})
+	}
+}
+
+// TestLockDownPolicyComplete 测试补全选项时锁定策略和最大重置次数的默认值
+func TestLockDownPolicyComplete(t *testing.T) {
+	cases := []struct {
+		name              string
+		opts              tetris.Options
+		wantPolicy        tetris.LockDownPolicy
+		wantMaxResetTimes int
+	}{
+		{name: "default-options", opts: tetris.DefaultOptions, wantPolicy: tetris.LockDownMoveReset, wantMaxResetTimes: 15},
+		{name: "zero", opts: tetris.Options{}, wantPolicy: tetris.LockDownStepReset},
+		// 已弃用的 LockDownReset 仅在未指定锁定策略时生效
+		{name: "deprecated-reset", opts: tetris.Options{LockDownReset: true}, wantPolicy: tetris.LockDownMoveReset},
+		{
+			name:       "explicit-policy",
+			opts:       tetris.Options{LockDownPolicy: tetris.LockDownInfinite, LockDownReset: true},
+			wantPolicy: tetris.LockDownInfinite,
+		},
+		{
+			name:              "negative-max-reset-times",
+			opts:              tetris.Options{LockDownPolicy: tetris.LockDownMoveReset, LockDelayMaxResetTimes: -1},
+			wantPolicy:        tetris.LockDownMoveReset,
+			wantMaxResetTimes: 0,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := c.opts
+			opts.Complete()
+			if opts.LockDownPolicy != c.wantPolicy {
+				t.Errorf("lock down policy: %s, expected %s", opts.LockDownPolicy, c.wantPolicy)
+			}
+			if opts.LockDelayMaxResetTimes != c.wantMaxResetTimes {
+				t.Errorf("lock delay max reset times: %d, expected %d", opts.LockDelayMaxResetTimes, c.wantMaxResetTimes)
+			}
+		})
+	}
+}
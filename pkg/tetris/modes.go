@@ -0,0 +1,129 @@
+package tetris
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GameMode 游戏模式
+//
+// 定义游戏的目标，达成任一目标时游戏以胜利结束，未设置任何目标时为无尽模式
+type GameMode struct {
+	// 模式名
+	Name string `json:"name"`
+	// 目标消除行数， 0 表示不限
+	GoalLines int `json:"goalLines,omitempty"`
+	// 目标级别，完成该级别时结束， 0 表示不限
+	GoalLevel int `json:"goalLevel,omitempty"`
+	// 时间限制，到时结束， 0 表示不限
+	TimeLimit time.Duration `json:"timeLimit,omitempty"`
+}
+
+// 内置游戏模式
+var (
+	// ModeEndless 无尽模式，直到方块堆满
+	ModeEndless = GameMode{Name: "Endless"}
+	// ModeSprint 竞速模式，尽快消除 40 行
+	ModeSprint = GameMode{Name: "Sprint", GoalLines: 40}
+	// ModeUltra 限时模式， 2 分钟内尽量得分
+	ModeUltra = GameMode{Name: "Ultra", TimeLimit: 2 * time.Minute}
+	// ModeMarathon 马拉松模式，完成 15 个级别或消除 150 行
+	ModeMarathon = GameMode{Name: "Marathon", GoalLines: 150, GoalLevel: 15}
+)
+
+// BuiltinGameModes 内置游戏模式列表
+var BuiltinGameModes = []GameMode{ModeEndless, ModeSprint, ModeUltra, ModeMarathon}
+
+// Goal 返回目标描述
+func (m GameMode) Goal() string {
+	var goals []string
+	if m.GoalLines > 0 {
+		goals = append(goals, fmt.Sprintf("clear %d lines", m.GoalLines))
+	}
+	if m.GoalLevel > 0 {
+		goals = append(goals, fmt.Sprintf("finish level %d", m.GoalLevel))
+	}
+	if m.TimeLimit > 0 {
+		goals = append(goals, fmt.Sprintf("score in %s", m.TimeLimit))
+	}
+	if len(goals) == 0 {
+		return "Play until top out"
+	}
+	goal := strings.Join(goals, " or ")
+	return strings.ToUpper(goal[:1]) + goal[1:]
+}
+
+// check 检查是否达成目标
+func (m GameMode) check(level, clearLines int, elapsed time.Duration) bool {
+	if m.GoalLines > 0 && clearLines >= m.GoalLines {
+		return true
+	}
+	if m.GoalLevel > 0 && level > m.GoalLevel {
+		return true
+	}
+	if m.TimeLimit > 0 && elapsed >= m.TimeLimit {
+		return true
+	}
+	return false
+}
+
+// GameResult 游戏结果
+type GameResult byte
+
+// GameResult 的枚举值
+const (
+	// ResultNone 无结果（游戏未结束或被中途停止）
+	ResultNone GameResult = iota
+	// ResultWon 达成游戏模式的目标
+	ResultWon
+	// ResultLost 方块堆满，游戏失败
+	ResultLost
+)
+
+// String 返回字符串表示
+func (r GameResult) String() string {
+	switch r {
+	case ResultNone:
+		return "None"
+	case ResultWon:
+		return "Won"
+	case ResultLost:
+		return "Lost"
+	}
+	return fmt.Sprintf("Invalid(%d)", r)
+}
+
+// TopOutType 导致游戏失败的顶出类型
+type TopOutType byte
+
+// TopOutType 的枚举值
+const (
+	// TopOutNone 未顶出
+	TopOutNone TopOutType = iota
+	// TopOutBlockOut 新方块出生时与场上方块重叠
+	TopOutBlockOut
+	// TopOutLockOut 方块锁定时全部位于可见行之上
+	TopOutLockOut
+	// TopOutPartialLockOut 方块锁定时部分位于可见行之上，仅在开启 Options.PartialLockOut 时发生
+	TopOutPartialLockOut
+	// TopOutGarbage 加入的垃圾行将方块顶出场外
+	TopOutGarbage
+)
+
+// String 返回字符串表示
+func (t TopOutType) String() string {
+	switch t {
+	case TopOutNone:
+		return "None"
+	case TopOutBlockOut:
+		return "Block Out"
+	case TopOutLockOut:
+		return "Lock Out"
+	case TopOutPartialLockOut:
+		return "Partial Lock Out"
+	case TopOutGarbage:
+		return "Garbage Out"
+	}
+	return fmt.Sprintf("Invalid(%d)", t)
+}
@@ -0,0 +1,119 @@
+package tetris_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+)
+
+// TestGameModeGoal 测试游戏模式的目标描述
+func TestGameModeGoal(t *testing.T) {
+	cases := []struct {
+		mode tetris.GameMode
+		want string
+	}{
+		{mode: tetris.ModeEndless, want: "Play until top out"},
+		{mode: tetris.ModeSprint, want: "Clear 40 lines"},
+		{mode: tetris.ModeUltra, want: "Score in 2m0s"},
+		{mode: tetris.ModeMarathon, want: "Clear 150 lines or finish level 15"},
+	}
+	for _, c := range cases {
+		if got := c.mode.Goal(); got != c.want {
+			t.Errorf("%s goal: %q, expected %q", c.mode.Name, got, c.want)
+		}
+	}
+}
+
+// fillRowUnderActive 将存档场的最底行填满，只留出活跃方块硬下落后落在该行的格子
+//
+// 硬下落活跃方块后恰好消除一行
+func fillRowUnderActive(snapshot *tetris.Snapshot) {
+	active := snapshot.ActiveTetromino
+	bottom := active.Row
+	for _, cell := range active.Cells() {
+		bottom = min(bottom, cell.Row())
+	}
+	row := []byte(snapshot.Field[0])
+	for i := range row {
+		row[i] = 'I'
+	}
+	for _, cell := range active.Cells() {
+		if cell.Row() == bottom {
+			row[cell.Column()] = '.'
+		}
+	}
+	snapshot.Field[0] = string(row)
+}
+
+// TestGameModeResult 测试达成游戏模式目标或方块堆满时的游戏结果
+func TestGameModeResult(t *testing.T) {
+	cases := []struct {
+		name       string
+		mode       tetris.GameMode
+		clearLine  bool
+		wait       int
+		hardDrops  int
+		wantResult tetris.GameResult
+	}{
+		{name: "lines", mode: tetris.GameMode{Name: "Test", GoalLines: 1}, clearLine: true, wantResult: tetris.ResultWon},
+		{name: "level", mode: tetris.GameMode{Name: "Test", GoalLevel: 1}, clearLine: true, wantResult: tetris.ResultWon},
+		{name: "lines-not-reached", mode: tetris.GameMode{Name: "Test", GoalLines: 2}, clearLine: true, wantResult: tetris.ResultNone},
+		{name: "time-limit", mode: tetris.GameMode{Name: "Test", TimeLimit: time.Second}, wait: 60, wantResult: tetris.ResultWon},
+		{name: "before-time-limit", mode: tetris.GameMode{Name: "Test", TimeLimit: time.Second}, wait: 59, wantResult: tetris.ResultNone},
+		{name: "top-out", mode: tetris.ModeSprint, hardDrops: 30, wantResult: tetris.ResultLost},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.Mode = c.mode
+			opts.LinesPerLevel = 1
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			if c.clearLine {
+				fillRowUnderActive(&snapshot)
+			}
+			game := restoreManualGame(t, snapshot)
+			events := game.Events()
+
+			if c.clearLine {
+				game.Input(tetris.OpHardDrop)
+			}
+			if c.wait > 0 {
+				if err := game.Step(c.wait); err != nil {
+					t.Fatalf("step error: %v", err)
+				}
+			}
+			for i := 0; i < c.hardDrops && game.State() == tetris.StateRunning; i++ {
+				game.Input(tetris.OpHardDrop)
+			}
+
+			frame := game.CurrentFrame()
+			if c.clearLine && frame.ClearLines != 1 {
+				t.Fatalf("clear lines: %d, expected 1", frame.ClearLines)
+			}
+			if frame.Result != c.wantResult {
+				t.Errorf("result: %s, expected %s", frame.Result, c.wantResult)
+			}
+			if frame.GameOver != (c.wantResult != tetris.ResultNone) {
+				t.Errorf("game over: %t, expected result %s", frame.GameOver, c.wantResult)
+			}
+			if c.wantResult == tetris.ResultNone {
+				return
+			}
+			var over *tetris.Event
+			for e := range events {
+				if e.Type == tetris.EventGameOver {
+					over = &e
+					break
+				}
+			}
+			if over == nil || over.Result != c.wantResult {
+				t.Errorf("game over event: %+v, expected result %s", over, c.wantResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package movegen
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// Move 移动方块的操作
+type Move byte
+
+// Move 的枚举值
+const (
+	// MoveLeft 左移一格
+	MoveLeft Move = iota
+	// MoveRight 右移一格
+	MoveRight
+	// MoveDASLeft 按住左移直到不能移动（ DAS ）
+	MoveDASLeft
+	// MoveDASRight 按住右移直到不能移动（ DAS ）
+	MoveDASRight
+	// MoveRotateRight 顺时针旋转 90 度
+	MoveRotateRight
+	// MoveRotateLeft 逆时针旋转 90 度
+	MoveRotateLeft
+	// MoveRotate180 旋转 180 度
+	MoveRotate180
+	// MoveSoftDrop 软降直到不能下落
+	MoveSoftDrop
+)
+
+// String 返回字符串表示
+func (m Move) String() string {
+	switch m {
+	case MoveLeft:
+		return "Left"
+	case MoveRight:
+		return "Right"
+	case MoveDASLeft:
+		return "DASLeft"
+	case MoveDASRight:
+		return "DASRight"
+	case MoveRotateRight:
+		return "RotateRight"
+	case MoveRotateLeft:
+		return "RotateLeft"
+	case MoveRotate180:
+		return "Rotate180"
+	case MoveSoftDrop:
+		return "SoftDrop"
+	}
+	return fmt.Sprintf("Invalid(%d)", m)
+}
+
+// BasicMoves 不含 DAS 的操作，左右移动每次一格
+var BasicMoves = []Move{MoveLeft, MoveRight, MoveRotateRight, MoveRotateLeft, MoveRotate180, MoveSoftDrop}
+
+// AllMoves 所有操作
+var AllMoves = []Move{
+	MoveLeft, MoveRight, MoveDASLeft, MoveDASRight,
+	MoveRotateRight, MoveRotateLeft, MoveRotate180, MoveSoftDrop,
+}
+
+// Placement 方块最终放置位置
+type Placement struct {
+	// 锁定时的方块位置和方向
+	Tetromino common.Tetromino
+	// 从起始位置到达该位置的最短操作序列，不含最后的硬降
+	Path []Move
+	// 锁定时的 T-Spin 类型
+	//
+	// 仅当最后一次操作为旋转且旋转后方块不能再下落时可能为 T-Spin
+	TSpin common.TSpinType
+}
+
+// Apply 对场上活跃方块执行操作，成功移动或旋转时返回 true
+func Apply(field *common.BitField, rs rotationsystems.RotationSystem, move Move) bool {
+	ok, _ := apply(field, rs, move)
+	return ok
+}
+
+// apply 对场上活跃方块执行操作，成功移动或旋转时返回 ok=true ，旋转时同时返回使用的踢墙
+func apply(field *common.BitField, rs rotationsystems.RotationSystem, move Move) (ok bool, kick rotationsystems.Kick) {
+	switch move {
+	case MoveLeft:
+		ok = field.MoveActiveTetromino(0, -1)
+	case MoveRight:
+		ok = field.MoveActiveTetromino(0, 1)
+	case MoveDASLeft:
+		ok = moveToEnd(field, 0, -1)
+	case MoveDASRight:
+		ok = moveToEnd(field, 0, 1)
+	case MoveRotateRight:
+		kick, ok = rs.RotateRight(field)
+	case MoveRotateLeft:
+		kick, ok = rs.RotateLeft(field)
+	case MoveRotate180:
+		kick, ok = rs.Rotate180(field)
+	case MoveSoftDrop:
+		ok = moveToEnd(field, -1, 0)
+	}
+	return ok, kick
+}
+
+// node 搜索节点
+type node struct {
+	tetromino common.Tetromino
+	// 到达该位置后直接锁定时的 T-Spin 类型
+	tSpin common.TSpinType
+}
+
+// placementKey 放置位置的唯一标识
+type placementKey struct {
+	cells string
+	tSpin common.TSpinType
+}
+
+// Generate 枚举方块从起始位置 start 出发，经 moves 中的操作可到达的所有最终放置位置
+//
+// field 中的活跃方块会被忽略。占用格子相同且 T-Spin 类型相同的位置视为同一放置位置，只返回操作数最少的路径。
+// 返回结果按首次到达的顺序排列，起始位置不合法时返回 nil
+func Generate(
+	field *common.BitField,
+	rs rotationsystems.RotationSystem,
+	start common.Tetromino,
+	moves []Move,
+) []Placement {
+	scratch := field.Clone()
+	startCopy := start
+	if !scratch.ChangeActiveTetromino(&startCopy) {
+		return nil
+	}
+
+	var ret []Placement
+	seen := make(map[placementKey]bool)
+	startNode := node{tetromino: start}
+	paths := map[node][]Move{startNode: nil}
+	queue := []node{startNode}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		path := paths[cur]
+
+		// 在该位置硬降后锁定
+		landed := cur.tetromino
+		_ = scratch.ChangeActiveTetromino(&landed)
+		tSpin := cur.tSpin
+		if moveToEnd(scratch, -1, 0) {
+			// 硬降时下落了，不再是 T-Spin
+			tSpin = common.TSpinNone
+		}
+		key := placementKey{cells: cellsKey(landed), tSpin: tSpin}
+		if !seen[key] {
+			seen[key] = true
+			ret = append(ret, Placement{Tetromino: landed, Path: path, TSpin: tSpin})
+		}
+
+		for _, move := range moves {
+			next := cur.tetromino
+			_ = scratch.ChangeActiveTetromino(&next)
+			ok, kick := apply(scratch, rs, move)
+			if !ok {
+				continue
+			}
+			nextNode := node{tetromino: next}
+			switch move {
+			case MoveRotateRight, MoveRotateLeft, MoveRotate180:
+				nextNode.tSpin = spinType(scratch, move, kick)
+			default:
+			}
+			if _, ok := paths[nextNode]; ok {
+				continue
+			}
+			paths[nextNode] = append(slices.Clone(path), move)
+			queue = append(queue, nextNode)
+		}
+	}
+
+	return ret
+}
+
+// spinType 返回旋转后立即锁定时的 T-Spin 类型
+//
+// 方块还能下落时不是 T-Spin 。 90 度旋转使用第 5 个踢墙测试时 T-Spin Mini 视为 T-Spin
+func spinType(field *common.BitField, move Move, kick rotationsystems.Kick) common.TSpinType {
+	if field.MoveActiveTetromino(-1, 0) {
+		_ = field.MoveActiveTetromino(1, 0)
+		return common.TSpinNone
+	}
+	tSpin := field.TSpinType()
+	if tSpin == common.TSpinMini && move != MoveRotate180 && kick.Index == 4 {
+		tSpin = common.TSpinFull
+	}
+	return tSpin
+}
+
+// moveToEnd 将活跃方块向指定方向一直移动到不能移动为止，至少移动一格时返回 true
+func moveToEnd(field *common.BitField, row, col int) bool {
+	moved := false
+	for field.MoveActiveTetromino(row, col) {
+		moved = true
+	}
+	return moved
+}
+
+// cellsKey 返回方块占用格子的有序列表的编码，用于判断不同方向的方块是否占用相同的格子
+func cellsKey(tetromino common.Tetromino) string {
+	cells := tetromino.Cells()
+	slices.SortFunc(cells, func(a, b common.Location) int {
+		if a.Row() != b.Row() {
+			return a.Row() - b.Row()
+		}
+		return a.Column() - b.Column()
+	})
+	return fmt.Sprint(cells)
+}
+
+// SameCells 判断两个方块是否为同一类型且占用相同的格子
+func SameCells(a, b common.Tetromino) bool {
+	return a.Type == b.Type && cellsKey(a) == cellsKey(b)
+}
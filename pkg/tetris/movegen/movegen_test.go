@@ -0,0 +1,251 @@
+package movegen_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/movegen"
+	_ "github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// newField 创建 20 行 10 列的测试用场
+//
+// rows 从上往下每行一个字符串，放在场的最底部， '#' 表示已填充的格子， '.' 表示空格子
+func newField(rows ...string) *common.BitField {
+	field := common.NewBitField(20, 10, nil)
+	for i, row := range rows {
+		for j := range row {
+			if row[j] == '#' {
+				field.SetTetromino(len(rows)-1-i, j, common.Garbage)
+			}
+		}
+	}
+	return field
+}
+
+// spawn 返回 20 行 10 列的场中方块的出生位置
+func spawn(tetrominoType common.TetrominoType) common.Tetromino {
+	if tetrominoType == common.O {
+		return common.Tetromino{Type: tetrominoType, Row: 18, Column: 4}
+	}
+	return common.Tetromino{Type: tetrominoType, Row: 17, Column: 3}
+}
+
+// find 返回与 target 占用格子相同且 T-Spin 类型为 tSpin 的放置位置
+func find(placements []movegen.Placement, target common.Tetromino, tSpin common.TSpinType) (movegen.Placement, bool) {
+	for _, p := range placements {
+		if movegen.SameCells(p.Tetromino, target) && p.TSpin == tSpin {
+			return p, true
+		}
+	}
+	return movegen.Placement{}, false
+}
+
+// TestGenerateEmptyField 测试空场中可到达的放置位置
+func TestGenerateEmptyField(t *testing.T) {
+	o1, _ := common.LookupPiece("O1")
+	i5, _ := common.LookupPiece("I5")
+	cases := []struct {
+		tetromino common.TetrominoType
+		want      int
+	}{
+		// 占用格子相同的方向视为同一位置
+		{tetromino: common.O, want: 9},
+		{tetromino: common.I, want: 7 + 10},
+		{tetromino: common.T, want: 8 + 9 + 8 + 9},
+		{tetromino: common.Z, want: 8 + 9},
+		// 非标准方块
+		{tetromino: o1, want: 10},
+		{tetromino: i5, want: 6 + 10},
+	}
+	srs := rotationsystems.SuperRotationSystem{}
+	for _, c := range cases {
+		t.Run(c.tetromino.String(), func(t *testing.T) {
+			placements := movegen.Generate(newField(), srs, spawn(c.tetromino), movegen.BasicMoves)
+			if len(placements) != c.want {
+				t.Errorf("placements: %d, expected %d", len(placements), c.want)
+			}
+		})
+	}
+}
+
+// TestGeneratePath 测试到达放置位置的最短操作序列
+func TestGeneratePath(t *testing.T) {
+	srs := rotationsystems.SuperRotationSystem{}
+	cases := []struct {
+		name   string
+		moves  []movegen.Move
+		target common.Tetromino
+		want   []movegen.Move
+	}{
+		{
+			name:   "hard-drop",
+			moves:  movegen.BasicMoves,
+			target: common.Tetromino{Type: common.T, Row: -1, Column: 3},
+		},
+		{
+			name:   "left-wall",
+			moves:  movegen.BasicMoves,
+			target: common.Tetromino{Type: common.T, Row: -1, Column: 0},
+			want:   []movegen.Move{movegen.MoveLeft, movegen.MoveLeft, movegen.MoveLeft},
+		},
+		{
+			name:   "das",
+			moves:  movegen.AllMoves,
+			target: common.Tetromino{Type: common.T, Row: -1, Column: 0},
+			want:   []movegen.Move{movegen.MoveDASLeft},
+		},
+		{
+			name:   "rotate-180",
+			moves:  movegen.BasicMoves,
+			target: common.Tetromino{Type: common.T, Row: 0, Column: 3, Dir: common.Dir2},
+			want:   []movegen.Move{movegen.MoveRotate180},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placements := movegen.Generate(newField(), srs, spawn(common.T), c.moves)
+			p, ok := find(placements, c.target, common.TSpinNone)
+			if !ok {
+				t.Fatalf("target %+v not reachable", c.target)
+			}
+			if !slices.Equal(p.Path, c.want) {
+				t.Errorf("path: %v, expected %v", p.Path, c.want)
+			}
+		})
+	}
+}
+
+// TestGenerateReachability 测试需要软降后移动或旋转才能到达的位置
+func TestGenerateReachability(t *testing.T) {
+	srs := rotationsystems.SuperRotationSystem{}
+	cases := []struct {
+		name      string
+		rows      []string
+		moves     []movegen.Move
+		start     common.Tetromino
+		target    common.Tetromino
+		tSpin     common.TSpinType
+		reachable bool
+	}{
+		{
+			// 软降到底后左移塞入屋檐下
+			name: "tuck",
+			rows: []string{
+				"######....",
+				"..........",
+				"..........",
+			},
+			moves:     movegen.BasicMoves,
+			start:     spawn(common.O),
+			target:    common.Tetromino{Type: common.O, Row: 0, Column: 0},
+			reachable: true,
+		},
+		{
+			name: "tuck-without-soft-drop",
+			rows: []string{
+				"######....",
+				"..........",
+				"..........",
+			},
+			moves:  []movegen.Move{movegen.MoveLeft, movegen.MoveRight},
+			start:  spawn(common.O),
+			target: common.Tetromino{Type: common.O, Row: 0, Column: 0},
+		},
+		{
+			name: "t-spin-double",
+			rows: []string{
+				"#.........",
+				"...#######",
+				"#.########",
+			},
+			moves:     movegen.BasicMoves,
+			start:     spawn(common.T),
+			target:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			tSpin:     common.TSpinFull,
+			reachable: true,
+		},
+		{
+			// 被封闭的空洞无法到达
+			name: "closed-hole",
+			rows: []string{
+				"##########",
+				"..........",
+				"..........",
+			},
+			moves:  movegen.BasicMoves,
+			start:  spawn(common.O),
+			target: common.Tetromino{Type: common.O, Row: 0, Column: 0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placements := movegen.Generate(newField(c.rows...), srs, c.start, c.moves)
+			p, ok := find(placements, c.target, c.tSpin)
+			if ok != c.reachable {
+				t.Fatalf("reachable: %v, expected %v", ok, c.reachable)
+			}
+			if !ok {
+				return
+			}
+
+			// 按路径操作后硬降应到达目标位置
+			field := newField(c.rows...)
+			start := c.start
+			field.ChangeActiveTetromino(&start)
+			for _, move := range p.Path {
+				if !movegen.Apply(field, srs, move) {
+					t.Fatalf("apply %s failed", move)
+				}
+			}
+			for field.MoveActiveTetromino(-1, 0) {
+			}
+			if !movegen.SameCells(*field.ActiveTetromino(), c.target) {
+				t.Errorf("landed: %+v, expected %+v", *field.ActiveTetromino(), c.target)
+			}
+		})
+	}
+}
+
+// TestGenerateInvalidStart 测试起始位置不合法时不返回放置位置
+func TestGenerateInvalidStart(t *testing.T) {
+	field := newField("##########")
+	start := common.Tetromino{Type: common.O, Row: 0, Column: 0}
+	if got := movegen.Generate(field, rotationsystems.SuperRotationSystem{}, start, movegen.BasicMoves); got != nil {
+		t.Errorf("placements: %v, expected nil", got)
+	}
+}
+
+// TestSameCells 测试判断方块是否占用相同的格子
+func TestSameCells(t *testing.T) {
+	cases := []struct {
+		a, b common.Tetromino
+		want bool
+	}{
+		{
+			a:    common.Tetromino{Type: common.I, Row: 0, Column: 3, Dir: common.DirR},
+			b:    common.Tetromino{Type: common.I, Row: 0, Column: 4, Dir: common.DirL},
+			want: true,
+		},
+		{
+			a:    common.Tetromino{Type: common.O, Row: 0, Column: 0, Dir: common.Dir0},
+			b:    common.Tetromino{Type: common.O, Row: 0, Column: 0, Dir: common.Dir2},
+			want: true,
+		},
+		{
+			a: common.Tetromino{Type: common.S, Row: 0, Column: 0},
+			b: common.Tetromino{Type: common.Z, Row: 0, Column: 0},
+		},
+		{
+			a: common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir0},
+			b: common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+		},
+	}
+	for _, c := range cases {
+		if got := movegen.SameCells(c.a, c.b); got != c.want {
+			t.Errorf("SameCells(%+v, %+v): %v, expected %v", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -2,11 +2,14 @@ package tetris
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
 
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
 	"github.com/yhlooo/go-tetris/pkg/tetris/randomizer"
 	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
 )
@@ -14,12 +17,29 @@ import (
 // Options 游戏选项
 type Options struct {
 	// 行列数
+	//
+	// 列数最多为 common.MaxBitFieldColumns
 	Rows, Columns int
+	// 可见行之上的隐藏行（消失区）数
+	//
+	// 大于 0 时方块按准则出生在可见行上方，出生后若下方没有阻挡则立即下落一格；为 0 时方块出生在可见行的最上方
+	HiddenRows int
+	// 是否开启 Partial Lock Out 规则
+	//
+	// 开启时方块锁定后有任何格子位于可见行之上即游戏失败，否则仅在方块全部位于可见行之上时（ Lock Out ）失败
+	PartialLockOut bool
 
 	// 是否开启暂存方块功能
 	HoldEnabled bool
 	// 提示的下个方块数量
 	ShowNextTetrominoes int
+	// 是否分析 Finesse 错误
+	//
+	// 关闭时 Stats.FinesseFaults 和 Event.FinesseFaults 总为 0
+	FinesseEnabled bool
+
+	// 游戏模式
+	Mode GameMode
 
 	// 初始级别
 	InitialLevel int
@@ -29,23 +49,73 @@ type Options struct {
 	SpeedController SpeedController
 	// 处理频率（单位： ticket/s ）
 	Frequency int
+	// 时钟
+	//
+	// 用于按处理频率自动推进游戏，为 ManualClock 时需要通过 Tetris.Step 手动推进
+	Clock Clock
+
+	// 自动重复移动延迟（ Delayed Auto Shift, DAS ）
+	//
+	// 按住移动键（ OpMoveLeftPress 、 OpMoveRightPress ）多久后开始自动重复移动
+	DAS time.Duration
+	// 自动重复移动间隔（ Auto Repeat Rate, ARR ）
+	//
+	// 开始自动重复移动后每次移动的间隔， 0 表示立即移动到底
+	ARR time.Duration
+	// 软下落速度倍数
+	//
+	// 按住软下落键（ OpSoftDropPress ）时下落速度相对当前级别下落速度的倍数
+	SoftDropFactor float64
 
 	// 锁定延迟
+	//
+	// 方块着地后经过该时长锁定，使用 LockDownClassic 时无效
 	LockDelay time.Duration
+	// 锁定策略，决定锁定延迟如何重置
+	//
+	// 为 LockDownDefault 时由 LockDownReset 决定
+	LockDownPolicy LockDownPolicy
 	// 通过旋转、移动可重置锁定延迟
+	//
+	// Deprecated: 使用 LockDownPolicy 。仅在 LockDownPolicy 为 LockDownDefault 时生效，
+	// 为 true 时相当于 LockDownMoveReset ，否则相当于 LockDownStepReset
 	LockDownReset bool
-	// 锁定延迟最大重置次数
+	// 锁定延迟最大重置次数，仅用于 LockDownMoveReset
 	//
 	// 0 表示可无限重置
 	LockDelayMaxResetTimes int
 
+	// 随机种子
+	//
+	// 仅在未指定随机生成器时用于创建 RandomizerName 对应的随机生成器，相同种子生成相同的方块序列。
+	// 为 0 时使用当前时间作为种子，实际使用的种子可通过 Frame.Seed 获取
+	Seed uint64
+	// 随机生成器名，可选值见 randomizer.Names
+	//
+	// 仅在未指定随机生成器时用于选择默认的随机生成器，为空时使用 7-Bag
+	RandomizerName string
+	// 方块集合名，可选值见 pieces.Names ，也可以是通过 pieces.Load 加载的方块集合
+	//
+	// 仅在未指定随机生成器时用于选择生成的方块，为空时使用七种标准方块
+	PieceSet string
 	// 随机生成器
 	Randomizer randomizer.Randomizer
 	// 评分器
 	Scorer Scorer
+	// 旋转系统名，可选值见 rotationsystems.Names
+	//
+	// 仅在未指定旋转系统时用于选择默认的旋转系统，为空时使用 SRS
+	RotationSystemName string
 	// 旋转系统
+	//
+	// 同时决定方块的形状和出生位置
 	RotationSystem rotationsystems.RotationSystem
 
+	// 输入记录器
+	//
+	// 非空时游戏过程中的输入将被记录，用于回放
+	Recorder *Recorder
+
 	Logger logr.Logger
 }
 
@@ -57,6 +127,16 @@ func (opts *Options) Complete() {
 	if opts.Columns == 0 {
 		opts.Columns = 10
 	}
+	if opts.Columns > common.MaxBitFieldColumns {
+		opts.Columns = common.MaxBitFieldColumns
+	}
+	if opts.HiddenRows < 0 {
+		opts.HiddenRows = 0
+	}
+
+	if opts.Mode == (GameMode{}) {
+		opts.Mode = ModeEndless
+	}
 
 	if opts.InitialLevel == 0 {
 		opts.InitialLevel = 1
@@ -70,15 +150,53 @@ func (opts *Options) Complete() {
 	if opts.Frequency == 0 {
 		opts.Frequency = 1000
 	}
+	if opts.Clock == nil {
+		opts.Clock = RealClock{}
+	}
+	if opts.SoftDropFactor <= 0 {
+		opts.SoftDropFactor = 20
+	}
+	if opts.LockDownPolicy == LockDownDefault {
+		if opts.LockDownReset {
+			opts.LockDownPolicy = LockDownMoveReset
+		} else {
+			opts.LockDownPolicy = LockDownStepReset
+		}
+	}
+	if opts.LockDelayMaxResetTimes < 0 {
+		opts.LockDelayMaxResetTimes = 0
+	}
 
 	if opts.Randomizer == nil {
-		opts.Randomizer = &randomizer.Bag7{}
+		if opts.Seed == 0 {
+			opts.Seed = uint64(time.Now().UnixNano())
+		}
+		set, err := pieces.Get(opts.PieceSet)
+		if err != nil {
+			opts.Logger.Info(fmt.Sprintf("WARN: %v, use %s instead", err, pieces.NameTetromino))
+			opts.PieceSet = pieces.NameTetromino
+			set, _ = pieces.Get(pieces.NameTetromino)
+		}
+		src := rand.NewPCG(opts.Seed, opts.Seed)
+		r, err := randomizer.NewWithPieces(opts.RandomizerName, src, set.Pieces)
+		if err != nil {
+			opts.Logger.Info(fmt.Sprintf("WARN: %v, use %s instead", err, randomizer.Name7Bag))
+			opts.RandomizerName = randomizer.Name7Bag
+			r, _ = randomizer.NewWithPieces(randomizer.Name7Bag, src, set.Pieces)
+		}
+		opts.Randomizer = r
 	}
 	if opts.Scorer == nil {
 		opts.Scorer = DefaultScorer()
 	}
 	if opts.RotationSystem == nil {
-		opts.RotationSystem = rotationsystems.SuperRotationSystem{}
+		rs, err := rotationsystems.New(opts.RotationSystemName)
+		if err != nil {
+			opts.Logger.Info(fmt.Sprintf("WARN: %v, use %s instead", err, rotationsystems.NameSRS))
+			opts.RotationSystemName = rotationsystems.NameSRS
+			rs = rotationsystems.SuperRotationSystem{}
+		}
+		opts.RotationSystem = rs
 	}
 }
 
@@ -96,30 +214,43 @@ type ScoreEvent struct {
 	HardDrop int
 	// 清除行数
 	ClearLines int
-	// T-Spin
-	TSpin bool
+	// T-Spin 类型
+	TSpin common.TSpinType
+	// 消行后场上是否没有任何方块
+	PerfectClear bool
+	// 上一次消行是否为困难消行（ Tetris 或 T-Spin 消行），用于计算 Back-to-Back
+	BackToBack bool
+	// 连击数，连续第 2 个消行的方块连击数为 1 ，以此类推
+	Combo int
 }
 
 // DefaultOptions 默认选项
 var DefaultOptions = Options{
-	Rows:    20,
-	Columns: 10,
+	Rows:       20,
+	Columns:    10,
+	HiddenRows: 20,
 
 	HoldEnabled:         true,
 	ShowNextTetrominoes: 3,
+	FinesseEnabled:      true,
+
+	Mode: ModeEndless,
 
 	InitialLevel:    1,
 	LinesPerLevel:   10,
 	SpeedController: DefaultSpeedController,
 	Frequency:       60,
+	Clock:           RealClock{},
+
+	DAS:            time.Millisecond * 167,
+	ARR:            time.Millisecond * 33,
+	SoftDropFactor: 20,
 
 	LockDelay:              time.Millisecond * 500,
-	LockDownReset:          true,
+	LockDownPolicy:         LockDownMoveReset,
 	LockDelayMaxResetTimes: 15,
 
-	Randomizer:     &randomizer.Bag7{},
-	Scorer:         DefaultScorer(),
-	RotationSystem: rotationsystems.SuperRotationSystem{},
+	Scorer: DefaultScorer(),
 
 	Logger: logr.Discard(),
 }
@@ -162,7 +293,6 @@ func DefaultSpeedController(level int) float64 {
 
 // DefaultScorer 默认评分器
 func DefaultScorer() Scorer {
-	b2b := false
 	return func(level int, event ScoreEvent) (int, []string) {
 		score := 0
 		var reason []string
@@ -183,7 +313,8 @@ func DefaultScorer() Scorer {
 		// 清行分
 		clearScore := 0
 		difficult := false
-		if event.TSpin {
+		switch event.TSpin {
+		case common.TSpinFull:
 			switch event.ClearLines {
 			case 1:
 				// T-Spin Single
@@ -201,7 +332,20 @@ func DefaultScorer() Scorer {
 				difficult = true
 				reason = append(reason, "T-Spin Triple")
 			}
-		} else {
+		case common.TSpinMini:
+			switch event.ClearLines {
+			case 1:
+				// T-Spin Mini Single
+				clearScore = 200
+				difficult = true
+				reason = append(reason, "T-Spin Mini Single")
+			case 2:
+				// T-Spin Mini Double
+				clearScore = 400
+				difficult = true
+				reason = append(reason, "T-Spin Mini Double")
+			}
+		default:
 			switch event.ClearLines {
 			case 1:
 				// Single Line
@@ -220,24 +364,60 @@ func DefaultScorer() Scorer {
 				clearScore = 800
 				difficult = true
 				reason = append(reason, "Tetris")
+			default:
+				if event.ClearLines > 4 {
+					// 自定义方块一次消除 5 行及以上，与 Tetris 一样每行 200 分且为困难消行
+					clearScore = 200 * event.ClearLines
+					difficult = true
+					reason = append(reason, fmt.Sprintf("%d Lines Clear", event.ClearLines))
+				}
 			}
 		}
 
-		if b2b && difficult {
+		if event.BackToBack && difficult {
 			// Back-to-Back
 			clearScore += clearScore / 2
 			reason = append(reason, "Back-to-Back")
 		}
-		if event.ClearLines > 0 {
-			b2b = difficult
-		}
 
 		score += clearScore
 
-		if event.ClearLines == 0 && event.TSpin {
-			// T-Spin
-			score += 400
-			reason = append(reason, "T-Spin")
+		if event.ClearLines > 0 && event.Combo > 0 {
+			// 连击
+			score += 50 * event.Combo
+			reason = append(reason, fmt.Sprintf("Combo %d", event.Combo))
+		}
+
+		if event.PerfectClear {
+			// 全消奖励， Back-to-Back Tetris 全消奖励更高
+			reason = append(reason, "Perfect Clear")
+			switch {
+			case event.ClearLines == 1:
+				score += 800
+			case event.ClearLines == 2:
+				score += 1200
+			case event.ClearLines == 3:
+				score += 1800
+			case event.ClearLines >= 4:
+				if event.BackToBack {
+					score += 3200
+				} else {
+					score += 2000
+				}
+			}
+		}
+
+		if event.ClearLines == 0 {
+			switch event.TSpin {
+			case common.TSpinFull:
+				// T-Spin
+				score += 400
+				reason = append(reason, "T-Spin")
+			case common.TSpinMini:
+				// T-Spin Mini
+				score += 100
+				reason = append(reason, "T-Spin Mini")
+			}
 		}
 
 		return score * level, reason
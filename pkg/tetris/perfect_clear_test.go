@@ -0,0 +1,98 @@
+package tetris_test
+
+import (
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+)
+
+// TestPerfectClear 测试消行后场上没有方块时的全消判定和奖励
+func TestPerfectClear(t *testing.T) {
+	i5, _ := common.LookupPiece("I5")
+	cases := []struct {
+		name      string
+		rows      []string
+		pieceSet  string
+		active    *common.Tetromino
+		wantPC    bool
+		wantScore int
+	}{
+		{
+			name: "perfect-clear-double",
+			rows: []string{
+				"...GGGGGGG",
+				"G.GGGGGGGG",
+			},
+			wantPC:    true,
+			wantScore: 300 + 1200,
+		},
+		{
+			name: "not-perfect-clear",
+			rows: []string{
+				"G.........",
+				"...GGGGGGG",
+				"G.GGGGGGGG",
+			},
+			wantScore: 300,
+		},
+		{
+			// 自定义方块一次消除 5 行
+			name:      "perfect-clear-five-lines",
+			rows:      []string{".GGGGGGGGG", ".GGGGGGGGG", ".GGGGGGGGG", ".GGGGGGGGG", ".GGGGGGGGG"},
+			pieceSet:  pieces.NamePentomino,
+			active:    &common.Tetromino{Type: i5, Row: 0, Column: -2, Dir: common.DirR},
+			wantPC:    true,
+			wantScore: 5*200 + 2000,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.PieceSet = c.pieceSet
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			setSnapshotField(&snapshot, c.rows...)
+			snapshot.ActiveTetromino = common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2}
+			if c.active != nil {
+				snapshot.ActiveTetromino = *c.active
+			}
+			game := restoreManualGame(t, snapshot)
+			events := game.Events()
+			game.Input(tetris.OpHardDrop)
+
+			var lockDown *tetris.Event
+			gotPC := false
+		drain:
+			for {
+				select {
+				case e := <-events:
+					switch e.Type {
+					case tetris.EventLockDown:
+						lockDown = &e
+					case tetris.EventPerfectClear:
+						gotPC = true
+					}
+				default:
+					break drain
+				}
+			}
+			if lockDown == nil {
+				t.Fatalf("no lock down event")
+			}
+			if lockDown.PerfectClear != c.wantPC {
+				t.Errorf("perfect clear: %v, expected %v", lockDown.PerfectClear, c.wantPC)
+			}
+			if gotPC != c.wantPC {
+				t.Errorf("perfect clear event: %v, expected %v", gotPC, c.wantPC)
+			}
+			if lockDown.Score != c.wantScore {
+				t.Errorf("score: %d (%v), expected %d", lockDown.Score, lockDown.Reason, c.wantScore)
+			}
+		})
+	}
+}
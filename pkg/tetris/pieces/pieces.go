@@ -0,0 +1,139 @@
+package pieces
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// 内置方块集合名
+const (
+	// NameTetromino 七种标准方块
+	NameTetromino = "tetromino"
+	// NamePentomino 由五个格子组成的 18 种方块（含镜像）
+	NamePentomino = "pentomino"
+	// NameBig 放大一倍的标准方块
+	NameBig = "big"
+	// NameTiny 由一至三个格子组成的小方块
+	NameTiny = "tiny"
+)
+
+// Names 所有内置方块集合名
+var Names = []string{NameTetromino, NamePentomino, NameBig, NameTiny}
+
+// PieceSet 方块集合
+type PieceSet struct {
+	// 集合名
+	Name string
+	// 集合中的方块类型
+	Pieces []common.TetrominoType
+}
+
+// setFile 方块集合文件格式
+type setFile struct {
+	Name string `json:"name"`
+	// 只有方块名的项引用已注册的方块，其它项注册为新方块
+	Pieces []common.PieceDef `json:"pieces"`
+}
+
+//go:embed sets/*.json
+var builtinSets embed.FS
+
+var (
+	setsLock sync.RWMutex
+	sets     = map[string]PieceSet{}
+)
+
+func init() {
+	// 按固定顺序加载，使内置方块的类型在每次运行中相同
+	for _, name := range Names {
+		data, err := builtinSets.ReadFile(path.Join("sets", name+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("read builtin piece set %q error: %v", name, err))
+		}
+		if _, err := Load(data); err != nil {
+			panic(fmt.Sprintf("load builtin piece set %q error: %v", name, err))
+		}
+	}
+}
+
+// Get 返回已加载的方块集合
+//
+// name 为空时返回七种标准方块的集合
+func Get(name string) (PieceSet, error) {
+	if name == "" {
+		name = NameTetromino
+	}
+	setsLock.RLock()
+	defer setsLock.RUnlock()
+	set, ok := sets[name]
+	if !ok {
+		return PieceSet{}, fmt.Errorf("unknown piece set: %q", name)
+	}
+	return set, nil
+}
+
+// Load 从 JSON 数据加载方块集合并注册其中的新方块，加载后可通过 Get 获取
+//
+// 方块类型按注册顺序分配，需要恢复包含自定义方块的存档时应以相同顺序加载。
+// 与 common.RegisterPiece 相同，应在开始游戏前调用
+func Load(data []byte) (PieceSet, error) {
+	var file setFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return PieceSet{}, fmt.Errorf("unmarshal piece set error: %w", err)
+	}
+	if file.Name == "" {
+		return PieceSet{}, fmt.Errorf("piece set has no name")
+	}
+	if len(file.Pieces) == 0 {
+		return PieceSet{}, fmt.Errorf("piece set %q has no pieces", file.Name)
+	}
+
+	setsLock.Lock()
+	defer setsLock.Unlock()
+	if _, ok := sets[file.Name]; ok {
+		return PieceSet{}, fmt.Errorf("piece set %q already loaded", file.Name)
+	}
+	// 先检查所有方块，避免加载失败时只注册了部分方块
+	names := map[string]bool{}
+	for i := range file.Pieces {
+		def := &file.Pieces[i]
+		if names[def.Name] {
+			return PieceSet{}, fmt.Errorf("duplicate piece %q in set %q", def.Name, file.Name)
+		}
+		names[def.Name] = true
+		if len(def.Shapes[common.Dir0]) == 0 {
+			// 引用已注册的方块
+			if t, ok := common.LookupPiece(def.Name); !ok || !t.IsPiece() {
+				return PieceSet{}, fmt.Errorf("piece %q in set %q not found", def.Name, file.Name)
+			}
+			continue
+		}
+		if err := def.Complete(); err != nil {
+			return PieceSet{}, fmt.Errorf("invalid piece in set %q: %w", file.Name, err)
+		}
+		if _, ok := common.LookupPiece(def.Name); ok {
+			return PieceSet{}, fmt.Errorf("piece %q in set %q already registered", def.Name, file.Name)
+		}
+	}
+
+	set := PieceSet{Name: file.Name}
+	for _, def := range file.Pieces {
+		if len(def.Shapes[common.Dir0]) == 0 {
+			t, _ := common.LookupPiece(def.Name)
+			set.Pieces = append(set.Pieces, t)
+			continue
+		}
+		t, err := common.RegisterPiece(def)
+		if err != nil {
+			return PieceSet{}, fmt.Errorf("register piece in set %q error: %w", file.Name, err)
+		}
+		set.Pieces = append(set.Pieces, t)
+	}
+	sets[set.Name] = set
+	return set, nil
+}
@@ -0,0 +1,124 @@
+package pieces_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+)
+
+// TestBuiltinSets 测试内置方块集合
+func TestBuiltinSets(t *testing.T) {
+	cases := []struct {
+		name  string
+		count int
+		cells int
+	}{
+		{name: "", count: 7, cells: 4},
+		{name: pieces.NameTetromino, count: 7, cells: 4},
+		{name: pieces.NamePentomino, count: 18, cells: 5},
+		{name: pieces.NameBig, count: 7, cells: 16},
+		{name: pieces.NameTiny, count: 4},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set, err := pieces.Get(c.name)
+			if err != nil {
+				t.Fatalf("get piece set error: %v", err)
+			}
+			if len(set.Pieces) != c.count {
+				t.Errorf("pieces count: %d, expected %d", len(set.Pieces), c.count)
+			}
+			for _, p := range set.Pieces {
+				if !p.IsPiece() {
+					t.Errorf("%s is not a piece", p)
+				}
+				for dir := common.Dir0; dir <= common.DirL; dir++ {
+					cells := common.Tetromino{Type: p, Dir: dir}.Cells()
+					if c.cells != 0 && len(cells) != c.cells {
+						t.Errorf("%s dir %s cells: %d, expected %d", p, dir, len(cells), c.cells)
+					}
+				}
+			}
+		})
+	}
+
+	set, _ := pieces.Get(pieces.NameTetromino)
+	want := []common.TetrominoType{common.I, common.J, common.L, common.O, common.S, common.T, common.Z}
+	if !slices.Equal(set.Pieces, want) {
+		t.Errorf("tetromino pieces: %v, expected %v", set.Pieces, want)
+	}
+	if _, err := pieces.Get("unknown"); err == nil {
+		t.Errorf("expected unknown piece set error")
+	}
+}
+
+// TestLoad 测试加载方块集合
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: `{"name": "test-valid", "pieces": [{"name": "TestDomino", "shapes": [[[0, 0], [0, 1]]]}, {"name": "T"}]}`,
+		},
+		{name: "invalid-json", data: `{`, wantErr: true},
+		{name: "no-name", data: `{"pieces": [{"name": "T"}]}`, wantErr: true},
+		{name: "no-pieces", data: `{"name": "test-no-pieces"}`, wantErr: true},
+		{name: "already-loaded", data: `{"name": "tiny", "pieces": [{"name": "T"}]}`, wantErr: true},
+		{
+			name:    "duplicate-piece",
+			data:    `{"name": "test-duplicate", "pieces": [{"name": "T"}, {"name": "T"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown-reference",
+			data:    `{"name": "test-unknown", "pieces": [{"name": "TestMissing"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "already-registered",
+			data:    `{"name": "test-registered", "pieces": [{"name": "I5", "shapes": [[[0, 0]]]}]}`,
+			wantErr: true,
+		},
+		{
+			// 检查失败时不注册集合中的其它方块
+			name:    "partial",
+			data:    `{"name": "test-partial", "pieces": [{"name": "TestPartial", "shapes": [[[0, 0]]]}, {"name": "TestMissing"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set, err := pieces.Load([]byte(c.data))
+			if (err != nil) != c.wantErr {
+				t.Fatalf("load error: %v, expected error: %t", err, c.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := pieces.Get(set.Name)
+			if err != nil {
+				t.Fatalf("get loaded piece set error: %v", err)
+			}
+			if !slices.Equal(got.Pieces, set.Pieces) {
+				t.Errorf("loaded pieces: %v, expected %v", got.Pieces, set.Pieces)
+			}
+		})
+	}
+
+	if _, ok := common.LookupPiece("TestPartial"); ok {
+		t.Errorf("piece of failed set registered")
+	}
+	domino, ok := common.LookupPiece("TestDomino")
+	if !ok {
+		t.Fatalf("piece TestDomino not registered")
+	}
+	// 只指定 Dir0 时其它方向由旋转得到
+	if got, want := (common.Tetromino{Type: domino, Dir: common.DirR}).Cells(), []common.Location{{1, 0}, {0, 0}}; !slices.Equal(got, want) {
+		t.Errorf("TestDomino R cells: %v, expected %v", got, want)
+	}
+}
@@ -1,18 +1,20 @@
 package randomizer
 
 import (
+	"encoding"
 	"math/rand/v2"
 	"sync"
-	"time"
 
 	"github.com/yhlooo/go-tetris/pkg/tetris/common"
 )
 
 // New7Bag 创建 7-Bag 生成器
+//
+// s 为 nil 时使用以当前时间为种子的随机源，生成的序列不可复现。需要可复现时应指定随机源或通过 tetris.Options.Seed 指定种子
 func New7Bag(s rand.Source) *Bag7 {
-	return &Bag7{
-		rand: rand.New(s),
-	}
+	b := &Bag7{src: s}
+	b.init()
+	return b
 }
 
 // Bag7 7-Bag 生成器
@@ -20,27 +22,25 @@ func New7Bag(s rand.Source) *Bag7 {
 // 以包为单位生成，每次生成含 7 种方块的 7 个方块，打乱顺序依次发出
 type Bag7 struct {
 	lock   sync.Mutex
+	src    rand.Source
 	rand   *rand.Rand
 	buffer [7]common.TetrominoType
 	i      int
 }
 
 var _ Randomizer = (*Bag7)(nil)
+var _ encoding.BinaryMarshaler = (*Bag7)(nil)
+var _ encoding.BinaryUnmarshaler = (*Bag7)(nil)
 
 // Next 获取下一个方块类型
 func (b *Bag7) Next() common.TetrominoType {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	if b.rand == nil {
-		b.rand = rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
-	}
+	b.init()
 
 	if b.buffer[0] == common.TetrominoNone {
-		b.buffer = [7]common.TetrominoType{
-			common.I, common.J, common.L, common.O,
-			common.S, common.T, common.Z,
-		}
+		b.buffer = allTypes
 		b.rand.Shuffle(7, func(i, j int) {
 			b.buffer[i], b.buffer[j] = b.buffer[j], b.buffer[i]
 		})
@@ -56,3 +56,49 @@ func (b *Bag7) Next() common.TetrominoType {
 	b.i++
 	return ret
 }
+
+// MarshalBinary 导出生成器状态
+//
+// 仅在随机源实现了 encoding.BinaryMarshaler （如 rand.PCG 、 rand.ChaCha8 ）时可用
+func (b *Bag7) MarshalBinary() ([]byte, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.init()
+
+	state := make([]byte, 0, len(b.buffer)+1)
+	for _, t := range b.buffer {
+		state = append(state, byte(t))
+	}
+	return marshalState(b.src, append(state, byte(b.i)))
+}
+
+// UnmarshalBinary 导入由 MarshalBinary 导出的生成器状态
+//
+// 随机源需与导出时类型一致且实现了 encoding.BinaryUnmarshaler
+func (b *Bag7) UnmarshalBinary(data []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.init()
+
+	state, err := unmarshalState(b.src, data, len(b.buffer)+1)
+	if err != nil {
+		return err
+	}
+	for i := range b.buffer {
+		b.buffer[i] = common.TetrominoType(state[i])
+	}
+	b.i = int(state[len(b.buffer)])
+	return nil
+}
+
+// init 初始化随机源
+func (b *Bag7) init() {
+	if b.src == nil {
+		b.src = defaultSource()
+	}
+	if b.rand == nil {
+		b.rand = rand.New(b.src)
+	}
+}
@@ -0,0 +1,120 @@
+package randomizer
+
+import (
+	"encoding"
+	"encoding/binary"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// New14Bag 创建 14-Bag 生成器
+//
+// 每包含每种方块各 2 个共 14 个方块。 s 为 nil 时的行为与 New7Bag 相同
+func New14Bag(s rand.Source) *Bag {
+	return NewBag(s, allTypes[:], 2)
+}
+
+// NewBag 创建包生成器
+//
+// 每包含 pieces 中的每种方块各 copies 个， copies 小于 1 时为 1 。 s 为 nil 时的行为与 New7Bag 相同
+func NewBag(s rand.Source, pieces []common.TetrominoType, copies int) *Bag {
+	copies = max(copies, 1)
+	b := &Bag{
+		src:    s,
+		pieces: slices.Clone(pieces),
+		buffer: make([]common.TetrominoType, len(pieces)*copies),
+	}
+	b.i = len(b.buffer)
+	b.init()
+	return b
+}
+
+// Bag 包生成器
+//
+// 以包为单位生成，每包含每种方块各若干个，打乱顺序依次发出
+type Bag struct {
+	lock   sync.Mutex
+	src    rand.Source
+	rand   *rand.Rand
+	pieces []common.TetrominoType
+	buffer []common.TetrominoType
+	i      int
+}
+
+var _ Randomizer = (*Bag)(nil)
+var _ encoding.BinaryMarshaler = (*Bag)(nil)
+var _ encoding.BinaryUnmarshaler = (*Bag)(nil)
+
+// Next 获取下一个方块类型
+func (b *Bag) Next() common.TetrominoType {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.init()
+
+	if len(b.buffer) == 0 {
+		return common.TetrominoNone
+	}
+	if b.i >= len(b.buffer) {
+		for i := range b.buffer {
+			b.buffer[i] = b.pieces[i%len(b.pieces)]
+		}
+		b.rand.Shuffle(len(b.buffer), func(i, j int) {
+			b.buffer[i], b.buffer[j] = b.buffer[j], b.buffer[i]
+		})
+		b.i = 0
+	}
+
+	ret := b.buffer[b.i]
+	b.i++
+	return ret
+}
+
+// MarshalBinary 导出生成器状态
+//
+// 仅在随机源实现了 encoding.BinaryMarshaler （如 rand.PCG 、 rand.ChaCha8 ）时可用
+func (b *Bag) MarshalBinary() ([]byte, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.init()
+
+	state := make([]byte, 0, len(b.buffer)+2)
+	for _, t := range b.buffer {
+		state = append(state, byte(t))
+	}
+	return marshalState(b.src, binary.BigEndian.AppendUint16(state, uint16(b.i)))
+}
+
+// UnmarshalBinary 导入由 MarshalBinary 导出的生成器状态
+//
+// 随机源需与导出时类型一致且实现了 encoding.BinaryUnmarshaler ，方块集合和包的大小需与导出时相同
+func (b *Bag) UnmarshalBinary(data []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.init()
+
+	state, err := unmarshalState(b.src, data, len(b.buffer)+2)
+	if err != nil {
+		return err
+	}
+	for i := range b.buffer {
+		b.buffer[i] = common.TetrominoType(state[i])
+	}
+	b.i = int(binary.BigEndian.Uint16(state[len(b.buffer):]))
+	return nil
+}
+
+// init 初始化随机源
+func (b *Bag) init() {
+	if b.src == nil {
+		b.src = defaultSource()
+	}
+	if b.rand == nil {
+		b.rand = rand.New(b.src)
+	}
+}
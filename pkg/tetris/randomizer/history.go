@@ -0,0 +1,119 @@
+package randomizer
+
+import (
+	"encoding"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// NewHistory 创建历史记录生成器
+//
+// rerolls 为最多抽取次数， history 为初始历史记录。
+// s 为 nil 时的行为与 New7Bag 相同
+func NewHistory(s rand.Source, rerolls int, history [4]common.TetrominoType) *History {
+	h := &History{
+		src:     s,
+		rerolls: max(rerolls, 1),
+		history: history,
+	}
+	h.init()
+	return h
+}
+
+// History TGM 风格的历史记录生成器
+//
+// 记录最近生成的 4 个方块，每次随机抽取，若抽到的方块在历史记录中则重新抽取，最多抽取 rerolls 次，使用最后一次抽取的结果。
+// 第一个方块只会是 I 、 J 、 L 、 T 之一
+type History struct {
+	lock    sync.Mutex
+	src     rand.Source
+	rand    *rand.Rand
+	rerolls int
+	history [4]common.TetrominoType
+	started bool
+}
+
+var _ Randomizer = (*History)(nil)
+var _ encoding.BinaryMarshaler = (*History)(nil)
+var _ encoding.BinaryUnmarshaler = (*History)(nil)
+
+// firstTypes 第一个方块可能的类型
+var firstTypes = [4]common.TetrominoType{common.I, common.J, common.L, common.T}
+
+// Next 获取下一个方块类型
+func (h *History) Next() common.TetrominoType {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.init()
+
+	var ret common.TetrominoType
+	if !h.started {
+		ret = firstTypes[h.rand.IntN(len(firstTypes))]
+		h.started = true
+	} else {
+		for i := 0; i < h.rerolls; i++ {
+			ret = allTypes[h.rand.IntN(len(allTypes))]
+			if !slices.Contains(h.history[:], ret) {
+				break
+			}
+		}
+	}
+
+	copy(h.history[:], h.history[1:])
+	h.history[len(h.history)-1] = ret
+	return ret
+}
+
+// MarshalBinary 导出生成器状态
+//
+// 仅在随机源实现了 encoding.BinaryMarshaler （如 rand.PCG 、 rand.ChaCha8 ）时可用
+func (h *History) MarshalBinary() ([]byte, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.init()
+
+	state := make([]byte, 0, len(h.history)+1)
+	for _, t := range h.history {
+		state = append(state, byte(t))
+	}
+	started := byte(0)
+	if h.started {
+		started = 1
+	}
+	return marshalState(h.src, append(state, started))
+}
+
+// UnmarshalBinary 导入由 MarshalBinary 导出的生成器状态
+//
+// 随机源需与导出时类型一致且实现了 encoding.BinaryUnmarshaler
+func (h *History) UnmarshalBinary(data []byte) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.init()
+
+	state, err := unmarshalState(h.src, data, len(h.history)+1)
+	if err != nil {
+		return err
+	}
+	for i := range h.history {
+		h.history[i] = common.TetrominoType(state[i])
+	}
+	h.started = state[len(h.history)] != 0
+	return nil
+}
+
+// init 初始化随机源
+func (h *History) init() {
+	if h.src == nil {
+		h.src = defaultSource()
+	}
+	if h.rand == nil {
+		h.rand = rand.New(h.src)
+	}
+}
@@ -0,0 +1,86 @@
+package randomizer
+
+import (
+	"encoding"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// NewNES 创建 NES 生成器
+//
+// s 为 nil 时的行为与 New7Bag 相同
+func NewNES(s rand.Source) *NES {
+	n := &NES{src: s}
+	n.init()
+	return n
+}
+
+// NES NES 版俄罗斯方块的生成器
+//
+// 先从 8 个值中随机抽取一个（第 8 个值不对应任何方块），若抽到第 8 个值或与上一个方块相同，则从 7 种方块中重新抽取一次，不再检查
+type NES struct {
+	lock sync.Mutex
+	src  rand.Source
+	rand *rand.Rand
+	last common.TetrominoType
+}
+
+var _ Randomizer = (*NES)(nil)
+var _ encoding.BinaryMarshaler = (*NES)(nil)
+var _ encoding.BinaryUnmarshaler = (*NES)(nil)
+
+// Next 获取下一个方块类型
+func (n *NES) Next() common.TetrominoType {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.init()
+
+	roll := n.rand.IntN(len(allTypes) + 1)
+	if roll == len(allTypes) || allTypes[roll] == n.last {
+		roll = n.rand.IntN(len(allTypes))
+	}
+	n.last = allTypes[roll]
+	return n.last
+}
+
+// MarshalBinary 导出生成器状态
+//
+// 仅在随机源实现了 encoding.BinaryMarshaler （如 rand.PCG 、 rand.ChaCha8 ）时可用
+func (n *NES) MarshalBinary() ([]byte, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.init()
+
+	return marshalState(n.src, []byte{byte(n.last)})
+}
+
+// UnmarshalBinary 导入由 MarshalBinary 导出的生成器状态
+//
+// 随机源需与导出时类型一致且实现了 encoding.BinaryUnmarshaler
+func (n *NES) UnmarshalBinary(data []byte) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.init()
+
+	state, err := unmarshalState(n.src, data, 1)
+	if err != nil {
+		return err
+	}
+	n.last = common.TetrominoType(state[0])
+	return nil
+}
+
+// init 初始化随机源
+func (n *NES) init() {
+	if n.src == nil {
+		n.src = defaultSource()
+	}
+	if n.rand == nil {
+		n.rand = rand.New(n.src)
+	}
+}
@@ -0,0 +1,88 @@
+package randomizer
+
+import (
+	"encoding"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// NewRandom 创建完全随机生成器
+//
+// s 为 nil 时的行为与 New7Bag 相同
+func NewRandom(s rand.Source) *Random {
+	return NewRandomOf(s, allTypes[:])
+}
+
+// NewRandomOf 创建从 pieces 中随机选择的完全随机生成器
+//
+// s 为 nil 时的行为与 New7Bag 相同
+func NewRandomOf(s rand.Source, pieces []common.TetrominoType) *Random {
+	r := &Random{src: s, pieces: slices.Clone(pieces)}
+	r.init()
+	return r
+}
+
+// Random 完全随机生成器
+//
+// 每次从所有方块中等概率随机选择一个，与之前生成的方块无关
+type Random struct {
+	lock   sync.Mutex
+	src    rand.Source
+	rand   *rand.Rand
+	pieces []common.TetrominoType
+}
+
+var _ Randomizer = (*Random)(nil)
+var _ encoding.BinaryMarshaler = (*Random)(nil)
+var _ encoding.BinaryUnmarshaler = (*Random)(nil)
+
+// Next 获取下一个方块类型
+func (r *Random) Next() common.TetrominoType {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.init()
+
+	if len(r.pieces) == 0 {
+		return common.TetrominoNone
+	}
+	return r.pieces[r.rand.IntN(len(r.pieces))]
+}
+
+// MarshalBinary 导出生成器状态
+//
+// 仅在随机源实现了 encoding.BinaryMarshaler （如 rand.PCG 、 rand.ChaCha8 ）时可用
+func (r *Random) MarshalBinary() ([]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.init()
+
+	return marshalState(r.src, nil)
+}
+
+// UnmarshalBinary 导入由 MarshalBinary 导出的生成器状态
+//
+// 随机源需与导出时类型一致且实现了 encoding.BinaryUnmarshaler
+func (r *Random) UnmarshalBinary(data []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.init()
+
+	_, err := unmarshalState(r.src, data, 0)
+	return err
+}
+
+// init 初始化随机源
+func (r *Random) init() {
+	if r.src == nil {
+		r.src = defaultSource()
+	}
+	if r.rand == nil {
+		r.rand = rand.New(r.src)
+	}
+}
@@ -1,9 +1,120 @@
 package randomizer
 
-import "github.com/yhlooo/go-tetris/pkg/tetris/common"
+import (
+	"encoding"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
 
 // Randomizer 方块随机生成器
 type Randomizer interface {
 	// Next 获取下一个方块类型
 	Next() common.TetrominoType
 }
+
+// 内置生成器名
+const (
+	// Name7Bag 7-Bag ，现代俄罗斯方块标准
+	Name7Bag = "7bag"
+	// Name14Bag 14-Bag
+	Name14Bag = "14bag"
+	// NameRandom 完全随机
+	NameRandom = "random"
+	// NameClassic 初代俄罗斯方块使用的生成器，即完全随机，与 NameRandom 相同
+	NameClassic = "classic"
+	// NameTGM TGM 风格历史记录生成器，重新抽取 4 次
+	NameTGM = "tgm"
+	// NameTGM2 TGM2 风格历史记录生成器，重新抽取 6 次
+	NameTGM2 = "tgm2"
+	// NameNES NES 版本的生成器，与上一个相同时重新抽取一次
+	NameNES = "nes"
+)
+
+// Names 所有内置生成器名
+var Names = []string{Name7Bag, Name14Bag, NameRandom, NameClassic, NameTGM, NameTGM2, NameNES}
+
+// New 根据名称创建内置生成器
+//
+// name 为空时创建 7-Bag 生成器
+func New(name string, s rand.Source) (Randomizer, error) {
+	switch name {
+	case "", Name7Bag:
+		return New7Bag(s), nil
+	case Name14Bag:
+		return New14Bag(s), nil
+	case NameRandom, NameClassic:
+		return NewRandom(s), nil
+	case NameTGM:
+		return NewHistory(s, 4, [4]common.TetrominoType{common.Z, common.Z, common.Z, common.Z}), nil
+	case NameTGM2:
+		return NewHistory(s, 6, [4]common.TetrominoType{common.Z, common.S, common.Z, common.S}), nil
+	case NameNES:
+		return NewNES(s), nil
+	}
+	return nil, fmt.Errorf("unknown randomizer: %q", name)
+}
+
+// NewWithPieces 根据名称创建从 pieces 中生成方块的生成器
+//
+// pieces 为空或恰为七种标准方块时与 New 相同。
+// 否则只支持 7bag （每包含每种方块各一个）、 14bag （每包含每种方块各两个）、 random 和 classic ，其它生成器只适用于标准方块
+func NewWithPieces(name string, s rand.Source, pieces []common.TetrominoType) (Randomizer, error) {
+	if len(pieces) == 0 || slices.Equal(pieces, allTypes[:]) {
+		return New(name, s)
+	}
+	switch name {
+	case "", Name7Bag:
+		return NewBag(s, pieces, 1), nil
+	case Name14Bag:
+		return NewBag(s, pieces, 2), nil
+	case NameRandom, NameClassic:
+		return NewRandomOf(s, pieces), nil
+	case NameTGM, NameTGM2, NameNES:
+		return nil, fmt.Errorf("randomizer %q only supports the seven tetrominoes", name)
+	}
+	return nil, fmt.Errorf("unknown randomizer: %q", name)
+}
+
+// allTypes 所有标准方块类型
+var allTypes = [7]common.TetrominoType{common.I, common.J, common.L, common.O, common.S, common.T, common.Z}
+
+// defaultSource 未指定随机源时使用的以当前时间为种子的随机源
+func defaultSource() rand.Source {
+	return rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano()))
+}
+
+// marshalState 导出生成器状态 state 和随机源状态
+//
+// 仅在随机源实现了 encoding.BinaryMarshaler （如 rand.PCG 、 rand.ChaCha8 ）时可用
+func marshalState(src rand.Source, state []byte) ([]byte, error) {
+	m, ok := src.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("random source %T can not be marshaled", src)
+	}
+	srcData, err := m.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("marshal random source error: %w", err)
+	}
+	return append(append(make([]byte, 0, len(state)+len(srcData)), state...), srcData...), nil
+}
+
+// unmarshalState 导入由 marshalState 导出的数据，恢复随机源状态并返回长度为 stateLen 的生成器状态
+//
+// 随机源需与导出时类型一致且实现了 encoding.BinaryUnmarshaler
+func unmarshalState(src rand.Source, data []byte, stateLen int) ([]byte, error) {
+	if len(data) < stateLen {
+		return nil, fmt.Errorf("invalid randomizer data length: %d", len(data))
+	}
+	u, ok := src.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return nil, fmt.Errorf("random source %T can not be unmarshaled", src)
+	}
+	if err := u.UnmarshalBinary(data[stateLen:]); err != nil {
+		return nil, fmt.Errorf("unmarshal random source error: %w", err)
+	}
+	return data[:stateLen], nil
+}
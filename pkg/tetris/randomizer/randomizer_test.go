@@ -0,0 +1,143 @@
+package randomizer_test
+
+import (
+	"encoding"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+	"github.com/yhlooo/go-tetris/pkg/tetris/randomizer"
+)
+
+// randomizerCases 各生成器以种子 1 生成的前 21 个方块
+//
+// 录像只记录种子，生成的序列改变会导致已有的录像无法正确回放
+var randomizerCases = []struct {
+	name     string
+	pieceSet string
+	want     string
+}{
+	{name: randomizer.Name7Bag, want: "J L O T S I Z S T L Z O J I T J Z O S I L"},
+	{name: randomizer.Name14Bag, want: "Z T I S T O I L J L S O J Z S T Z I T J L"},
+	{name: randomizer.NameRandom, want: "Z I Z Z J Z T I O L L Z L O I Z I J L J J"},
+	{name: randomizer.NameClassic, want: "Z I Z Z J Z T I O L L Z L O I Z I J L J J"},
+	{name: randomizer.NameTGM, want: "I J T O L Z I J S T L Z J S I T O L J S J"},
+	{name: randomizer.NameTGM2, want: "I J T O L Z I J S T L Z J S I T O L J S Z"},
+	{name: randomizer.NameNES, want: "I O S J Z L T O S T L O T I J Z L O S J Z"},
+	{name: randomizer.Name7Bag, pieceSet: pieces.NameTiny, want: "I2 I3 L3 O1 I2 I3 L3 O1 L3 I2 O1 I3 I3 L3 I2 O1 O1 I3 I2 L3 I2"},
+	{name: randomizer.Name14Bag, pieceSet: pieces.NameTiny, want: "L3 I3 I3 I2 O1 I2 L3 O1 I3 O1 O1 I3 L3 I2 L3 I2 L3 I2 O1 I3 L3"},
+	{name: randomizer.NameRandom, pieceSet: pieces.NameTiny, want: "O1 L3 O1 O1 I2 I3 I3 I2 L3 O1 I2 I3 L3 I3 I2 O1 O1 I3 I3 I3 L3"},
+	{name: randomizer.NameClassic, pieceSet: pieces.NameTiny, want: "O1 L3 O1 O1 I2 I3 I3 I2 L3 O1 I2 I3 L3 I3 I2 O1 O1 I3 I3 I3 L3"},
+}
+
+// newTestRandomizer 创建使用指定种子的生成器
+func newTestRandomizer(t *testing.T, name, pieceSet string, seed uint64) randomizer.Randomizer {
+	t.Helper()
+	set, err := pieces.Get(pieceSet)
+	if err != nil {
+		t.Fatalf("get piece set error: %v", err)
+	}
+	r, err := randomizer.NewWithPieces(name, rand.NewPCG(seed, seed), set.Pieces)
+	if err != nil {
+		t.Fatalf("new randomizer error: %v", err)
+	}
+	return r
+}
+
+// sequence 返回生成器接下来生成的 n 个方块
+func sequence(r randomizer.Randomizer, n int) []common.TetrominoType {
+	ret := make([]common.TetrominoType, n)
+	for i := range ret {
+		ret[i] = r.Next()
+	}
+	return ret
+}
+
+// TestSeedSequence 测试相同种子生成固定的方块序列
+func TestSeedSequence(t *testing.T) {
+	for _, c := range randomizerCases {
+		t.Run(c.name+"/"+c.pieceSet, func(t *testing.T) {
+			var names []string
+			for _, p := range sequence(newTestRandomizer(t, c.name, c.pieceSet, 1), 21) {
+				names = append(names, p.String())
+			}
+			if got := strings.Join(names, " "); got != c.want {
+				t.Errorf("sequence mismatch:\n got: %s\nwant: %s", got, c.want)
+			}
+
+			a := sequence(newTestRandomizer(t, c.name, c.pieceSet, 2), 100)
+			b := sequence(newTestRandomizer(t, c.name, c.pieceSet, 2), 100)
+			if !slices.Equal(a, b) {
+				t.Errorf("sequences with the same seed mismatch:\n%v\n%v", a, b)
+			}
+		})
+	}
+}
+
+// TestMarshalBinary 测试导出状态后恢复的生成器继续生成相同的序列
+func TestMarshalBinary(t *testing.T) {
+	for _, c := range randomizerCases {
+		t.Run(c.name+"/"+c.pieceSet, func(t *testing.T) {
+			r := newTestRandomizer(t, c.name, c.pieceSet, 3)
+			sequence(r, 10)
+			data, err := r.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("marshal error: %v", err)
+			}
+
+			restored := newTestRandomizer(t, c.name, c.pieceSet, 4)
+			if err := restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+			want := sequence(r, 50)
+			if got := sequence(restored, 50); !slices.Equal(got, want) {
+				t.Errorf("sequence mismatch:\n got: %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+// TestBag 测试包生成器每包恰好包含每种方块各 copies 个
+func TestBag(t *testing.T) {
+	cases := []struct {
+		name   string
+		pieces []common.TetrominoType
+		copies int
+	}{
+		{name: "7bag", pieces: []common.TetrominoType{common.I, common.J, common.L, common.O, common.S, common.T, common.Z}, copies: 1},
+		{name: "14bag", pieces: []common.TetrominoType{common.I, common.J, common.L, common.O, common.S, common.T, common.Z}, copies: 2},
+		{name: "two-pieces", pieces: []common.TetrominoType{common.I, common.T}, copies: 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := randomizer.NewBag(rand.NewPCG(5, 5), c.pieces, c.copies)
+			for bag := 0; bag < 10; bag++ {
+				counts := map[common.TetrominoType]int{}
+				for _, p := range sequence(r, len(c.pieces)*c.copies) {
+					counts[p]++
+				}
+				for _, p := range c.pieces {
+					if counts[p] != c.copies {
+						t.Fatalf("bag %d: %s appears %d times, expected %d", bag, p, counts[p], c.copies)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestNewWithPieces 测试只适用于标准方块的生成器不支持其它方块集合
+func TestNewWithPieces(t *testing.T) {
+	set, err := pieces.Get(pieces.NamePentomino)
+	if err != nil {
+		t.Fatalf("get piece set error: %v", err)
+	}
+	for _, name := range []string{randomizer.NameTGM, randomizer.NameTGM2, randomizer.NameNES, "unknown"} {
+		if _, err := randomizer.NewWithPieces(name, rand.NewPCG(1, 1), set.Pieces); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package tetris
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReplayVersion 当前录像格式版本
+const ReplayVersion = 1
+
+// Replay 游戏录像
+//
+// 记录游戏选项、随机种子以及每次输入的操作指令、加入的垃圾行和所在周期，可通过 Player 回放。
+// 仅在游戏使用由种子创建的默认随机生成器时可准确复现
+type Replay struct {
+	// 录像格式版本
+	Version int `json:"version"`
+	// 随机种子
+	Seed uint64 `json:"seed"`
+	// 游戏选项
+	Options SavedOptions `json:"options"`
+	// 输入记录
+	Inputs []ReplayInput `json:"inputs"`
+	// 游戏结束时所在周期
+	EndTickets int64 `json:"endTickets"`
+}
+
+// SavedOptions 录像、存档中记录的游戏选项
+//
+// 仅包含可序列化的选项，其余选项在回放、恢复时由调用方提供
+type SavedOptions struct {
+	Rows                   int            `json:"rows"`
+	Columns                int            `json:"columns"`
+	HoldEnabled            bool           `json:"holdEnabled"`
+	ShowNextTetrominoes    int            `json:"showNextTetrominoes"`
+	FinesseEnabled         bool           `json:"finesseEnabled"`
+	Mode                   GameMode       `json:"mode"`
+	InitialLevel           int            `json:"initialLevel"`
+	LinesPerLevel          int            `json:"linesPerLevel"`
+	Frequency              int            `json:"frequency"`
+	DAS                    time.Duration  `json:"das"`
+	ARR                    time.Duration  `json:"arr"`
+	SoftDropFactor         float64        `json:"softDropFactor"`
+	LockDelay              time.Duration  `json:"lockDelay"`
+	LockDownPolicy         LockDownPolicy `json:"lockDownPolicy"`
+	LockDelayMaxResetTimes int            `json:"lockDelayMaxResetTimes"`
+	RandomizerName         string         `json:"randomizerName,omitempty"`
+	RotationSystemName     string         `json:"rotationSystemName,omitempty"`
+	PieceSet               string         `json:"pieceSet,omitempty"`
+	HiddenRows             int            `json:"hiddenRows,omitempty"`
+	PartialLockOut         bool           `json:"partialLockOut,omitempty"`
+}
+
+// newSavedOptions 从游戏选项创建 SavedOptions
+func newSavedOptions(opts Options) SavedOptions {
+	return SavedOptions{
+		Rows:                   opts.Rows,
+		Columns:                opts.Columns,
+		HoldEnabled:            opts.HoldEnabled,
+		ShowNextTetrominoes:    opts.ShowNextTetrominoes,
+		FinesseEnabled:         opts.FinesseEnabled,
+		Mode:                   opts.Mode,
+		InitialLevel:           opts.InitialLevel,
+		LinesPerLevel:          opts.LinesPerLevel,
+		Frequency:              opts.Frequency,
+		DAS:                    opts.DAS,
+		ARR:                    opts.ARR,
+		SoftDropFactor:         opts.SoftDropFactor,
+		LockDelay:              opts.LockDelay,
+		LockDownPolicy:         opts.LockDownPolicy,
+		LockDelayMaxResetTimes: opts.LockDelayMaxResetTimes,
+		RandomizerName:         opts.RandomizerName,
+		RotationSystemName:     opts.RotationSystemName,
+		PieceSet:               opts.PieceSet,
+		HiddenRows:             opts.HiddenRows,
+		PartialLockOut:         opts.PartialLockOut,
+	}
+}
+
+// ApplyTo 将记录的选项应用到 opts
+func (o SavedOptions) ApplyTo(opts *Options) {
+	opts.Rows = o.Rows
+	opts.Columns = o.Columns
+	opts.HoldEnabled = o.HoldEnabled
+	opts.ShowNextTetrominoes = o.ShowNextTetrominoes
+	opts.FinesseEnabled = o.FinesseEnabled
+	opts.Mode = o.Mode
+	opts.InitialLevel = o.InitialLevel
+	opts.LinesPerLevel = o.LinesPerLevel
+	opts.Frequency = o.Frequency
+	opts.DAS = o.DAS
+	opts.ARR = o.ARR
+	opts.SoftDropFactor = o.SoftDropFactor
+	opts.LockDelay = o.LockDelay
+	opts.LockDownPolicy = o.LockDownPolicy
+	opts.LockDelayMaxResetTimes = o.LockDelayMaxResetTimes
+	opts.RandomizerName = o.RandomizerName
+	opts.PieceSet = o.PieceSet
+	opts.HiddenRows = o.HiddenRows
+	opts.PartialLockOut = o.PartialLockOut
+	if o.RotationSystemName != "" {
+		// 按记录的名称重新选择旋转系统
+		opts.RotationSystemName = o.RotationSystemName
+		opts.RotationSystem = nil
+	}
+}
+
+// ReplayInput 一次输入记录
+type ReplayInput struct {
+	// 输入时所在周期（游戏开始后已处理的周期数）
+	Ticket int64 `json:"ticket"`
+	// 操作指令
+	Op Op `json:"op"`
+	// 加入的垃圾行，非空时表示该记录为一次 Tetris.AddGarbage 调用而非操作指令
+	Garbage *ReplayGarbage `json:"garbage,omitempty"`
+}
+
+// ReplayGarbage 一次加入垃圾行的记录
+type ReplayGarbage struct {
+	// 行数
+	Lines int `json:"lines"`
+	// 空洞所在列
+	HoleColumn int `json:"holeColumn"`
+}
+
+// NewRecorder 创建 Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Recorder 输入记录器
+//
+// 设置到 Options.Recorder 后，游戏过程中的输入会被记录下来
+type Recorder struct {
+	lock   sync.Mutex
+	replay Replay
+}
+
+// Replay 获取当前已记录的录像
+func (r *Recorder) Replay() Replay {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ret := r.replay
+	ret.Inputs = append([]ReplayInput(nil), r.replay.Inputs...)
+	return ret
+}
+
+// begin 开始记录
+func (r *Recorder) begin(opts Options) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.replay = Replay{
+		Version: ReplayVersion,
+		Seed:    opts.Seed,
+		Options: newSavedOptions(opts),
+	}
+}
+
+// record 记录一次输入
+func (r *Recorder) record(ticket int64, op Op) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.replay.Inputs = append(r.replay.Inputs, ReplayInput{Ticket: ticket, Op: op})
+}
+
+// recordGarbage 记录一次加入垃圾行
+func (r *Recorder) recordGarbage(ticket int64, lines, holeColumn int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.replay.Inputs = append(r.replay.Inputs, ReplayInput{
+		Ticket:  ticket,
+		Garbage: &ReplayGarbage{Lines: lines, HoleColumn: holeColumn},
+	})
+}
+
+// end 结束记录
+func (r *Recorder) end(ticket int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.replay.EndTickets = ticket
+}
+
+// NewPlayer 创建录像播放器
+//
+// opts 用于提供录像中未记录的选项（如评分器、旋转系统、日志等），录像中记录的选项、随机种子会覆盖 opts 中对应的值
+func NewPlayer(replay Replay, opts Options) (*Player, error) {
+	if replay.Version != ReplayVersion {
+		return nil, fmt.Errorf("unsupported replay version: %d", replay.Version)
+	}
+
+	if n := len(replay.Inputs); n > 0 && replay.EndTickets < replay.Inputs[n-1].Ticket {
+		// 录像未正常结束，回放到最后一次输入
+		replay.EndTickets = replay.Inputs[n-1].Ticket
+	}
+
+	replay.Options.ApplyTo(&opts)
+	opts.Seed = replay.Seed
+	opts.Randomizer = nil
+	opts.Clock = ManualClock{}
+	opts.Recorder = nil
+
+	return &Player{
+		replay: replay,
+		tetris: NewTetris(opts),
+	}, nil
+}
+
+// Player 录像播放器
+//
+// 将录像中的输入按记录的周期送入新的游戏实例，逐帧复现游戏过程
+type Player struct {
+	lock    sync.Mutex
+	replay  Replay
+	tetris  Tetris
+	ticket  int64
+	nextOpI int
+}
+
+// Tetris 返回用于回放的游戏实例
+//
+// 可通过其 Frames 、 CurrentFrame 方法获取回放画面，但不应直接对其输入操作
+func (p *Player) Tetris() Tetris {
+	return p.tetris
+}
+
+// Start 开始回放
+func (p *Player) Start(ctx context.Context) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if err := p.tetris.Start(ctx); err != nil {
+		return err
+	}
+	p.inputUntilNow()
+	return nil
+}
+
+// Step 回放推进指定周期数
+func (p *Player) Step(tickets int) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for i := 0; i < tickets && !p.done(); i++ {
+		if err := p.step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlayToEnd 回放到录像结束
+func (p *Player) PlayToEnd() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for !p.done() {
+		if err := p.step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Done 是否已回放到录像结束
+func (p *Player) Done() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.done()
+}
+
+// done 是否已回放到录像结束
+func (p *Player) done() bool {
+	return p.ticket >= p.replay.EndTickets || p.tetris.State() == StateFinished
+}
+
+// step 推进一个周期并输入该周期的操作
+func (p *Player) step() error {
+	if err := p.tetris.Step(1); err != nil {
+		return err
+	}
+	p.ticket++
+	p.inputUntilNow()
+	return nil
+}
+
+// inputUntilNow 输入当前周期及之前的所有操作
+func (p *Player) inputUntilNow() {
+	for p.nextOpI < len(p.replay.Inputs) && p.replay.Inputs[p.nextOpI].Ticket <= p.ticket {
+		input := p.replay.Inputs[p.nextOpI]
+		if input.Garbage != nil {
+			_ = p.tetris.AddGarbage(input.Garbage.Lines, input.Garbage.HoleColumn)
+		} else {
+			p.tetris.Input(input.Op)
+		}
+		p.nextOpI++
+	}
+}
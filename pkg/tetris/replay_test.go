@@ -0,0 +1,92 @@
+package tetris_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+)
+
+// TestRecordPlayToEnd 测试录像回放到结束时的帧与录制时的帧相同
+func TestRecordPlayToEnd(t *testing.T) {
+	cases := []struct {
+		name    string
+		modify  func(opts *tetris.Options)
+		garbage bool
+	}{
+		{name: "default", modify: func(*tetris.Options) {}},
+		{name: "garbage", modify: func(*tetris.Options) {}, garbage: true},
+		{name: "no-hold", modify: func(opts *tetris.Options) {
+			opts.HoldEnabled = false
+		}},
+		{name: "high-level-no-reset", modify: func(opts *tetris.Options) {
+			opts.InitialLevel = 10
+			opts.LockDownPolicy = tetris.LockDownStepReset
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := tetris.NewRecorder()
+			opts := tetris.DefaultOptions
+			opts.Seed = 7
+			opts.Recorder = recorder
+			c.modify(&opts)
+			game := newManualGame(t, opts)
+			runScript(t, game, newScript(2, 300, c.garbage))
+			want := game.CurrentFrame()
+			if err := game.Stop(); err != nil {
+				t.Fatalf("stop game error: %v", err)
+			}
+
+			// 经 JSON 序列化后回放
+			data, err := json.Marshal(recorder.Replay())
+			if err != nil {
+				t.Fatalf("marshal replay error: %v", err)
+			}
+			var replay tetris.Replay
+			if err := json.Unmarshal(data, &replay); err != nil {
+				t.Fatalf("unmarshal replay error: %v", err)
+			}
+			player, err := tetris.NewPlayer(replay, tetris.Options{})
+			if err != nil {
+				t.Fatalf("new player error: %v", err)
+			}
+			if err := player.Start(context.Background()); err != nil {
+				t.Fatalf("start player error: %v", err)
+			}
+			defer func() { _ = player.Tetris().Stop() }()
+			if err := player.PlayToEnd(); err != nil {
+				t.Fatalf("play to end error: %v", err)
+			}
+
+			got := player.Tetris().CurrentFrame()
+			if want.GameOver && !got.GameOver {
+				t.Errorf("replay not over, expected over")
+			}
+			got.GameOver, want.GameOver = false, false
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("frame mismatch:\n got: %+v\nwant: %+v", got, want)
+			}
+		})
+	}
+}
+
+// TestNewPlayerUnsupportedVersion 测试不支持的录像版本返回错误
+func TestNewPlayerUnsupportedVersion(t *testing.T) {
+	cases := []struct {
+		version int
+		wantErr bool
+	}{
+		{version: 0, wantErr: true},
+		{version: tetris.ReplayVersion, wantErr: false},
+		{version: tetris.ReplayVersion + 1, wantErr: true},
+	}
+	for _, c := range cases {
+		_, err := tetris.NewPlayer(tetris.Replay{Version: c.version, Seed: 1}, tetris.Options{})
+		if (err != nil) != c.wantErr {
+			t.Errorf("version %d: error %v, expected error: %t", c.version, err, c.wantErr)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package rotationsystems
+
+import (
+	"slices"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// ArikaRotationSystem Arika 旋转系统（ ARS ），即 TGM 系列使用的旋转系统
+//
+// 旋转受阻时依次尝试向右、向左移动一格， I 方块不踢墙；方块定义中有踢墙表时使用定义的踢墙表。
+// J L T 方块适用中心列规则：按从上到下、从左到右的顺序，旋转后第一个受阻的格子位于 3x3 区域的中间列时不踢墙。
+// 不支持 180 度旋转。
+//
+// 参考 https://tetris.wiki/Arika_Rotation_System
+type ArikaRotationSystem struct{}
+
+var _ RotationSystem = ArikaRotationSystem{}
+
+// arsWallKickData ARS 踢墙数据
+var arsWallKickData = []common.Location{{0, 0}, {0, +1}, {0, -1}}
+
+// Spawn 返回指定类型的新方块的出生位置
+//
+// 方块出生在场的最上方两行，三格宽的方块偏左
+func (ArikaRotationSystem) Spawn(tetrominoType common.TetrominoType, rows, cols int) common.Tetromino {
+	if !isStandard(tetrominoType) {
+		return spawnPiece(tetrominoType, rows, cols, common.ShapesARS)
+	}
+	row := rows - 2
+	if tetrominoType == common.I {
+		row = rows - 3
+	}
+	return common.Tetromino{
+		Type:   tetrominoType,
+		Row:    row,
+		Column: cols/2 - 2,
+		Dir:    common.Dir0,
+		Shapes: common.ShapesARS,
+	}
+}
+
+// RotateRight 将场上活跃方块顺时针旋转 90 度
+func (ars ArikaRotationSystem) RotateRight(field common.MutableField) (Kick, bool) {
+	return ars.rotate(field, 1)
+}
+
+// RotateLeft 将场上活跃方块逆时针旋转 90 度
+func (ars ArikaRotationSystem) RotateLeft(field common.MutableField) (Kick, bool) {
+	return ars.rotate(field, -1)
+}
+
+// Rotate180 不支持，总是返回 ok=false
+func (ArikaRotationSystem) Rotate180(common.MutableField) (Kick, bool) {
+	return Kick{}, false
+}
+
+// rotate 旋转
+func (ArikaRotationSystem) rotate(field common.MutableField, dir int) (Kick, bool) {
+	tetromino := field.ActiveTetromino()
+	if tetromino == nil {
+		return Kick{}, false
+	}
+
+	oldDir := tetromino.Dir
+	oldCol := tetromino.Column
+	tetromino.Dir = common.TetrominoDir((int(oldDir) + dir + 4) % 4)
+	if field.IsValid() {
+		return Kick{}, true
+	}
+
+	// 确定踢墙数据
+	def, _ := common.Piece(tetromino.Type)
+	wallKickData := def.Kick(oldDir, tetromino.Dir)
+	switch {
+	case wallKickData != nil:
+	case tetromino.Type == common.I, tetromino.Type == common.O:
+	case tetromino.Type == common.J, tetromino.Type == common.L, tetromino.Type == common.T:
+		if !centerColumnBlocked(field, *tetromino) {
+			wallKickData = arsWallKickData
+		}
+	default:
+		wallKickData = arsWallKickData
+	}
+	oldRow := tetromino.Row
+	for i, wallKick := range wallKickData {
+		if i == 0 && wallKick == (common.Location{}) {
+			// 已尝试过原地旋转
+			continue
+		}
+		tetromino.Row = oldRow + wallKick.Row()
+		tetromino.Column = oldCol + wallKick.Column()
+		if field.IsValid() {
+			return Kick{Index: i, Offset: wallKick}, true
+		}
+	}
+
+	// 旋转失败，还原
+	tetromino.Dir = oldDir
+	tetromino.Row = oldRow
+	tetromino.Column = oldCol
+	return Kick{}, false
+}
+
+// centerColumnBlocked 按从上到下、从左到右的顺序，方块第一个受阻的格子是否位于 3x3 区域的中间列
+func centerColumnBlocked(field common.MutableField, tetromino common.Tetromino) bool {
+	_, cols := field.Size()
+	cells := tetromino.Cells()
+	slices.SortFunc(cells, func(a, b common.Location) int {
+		if a.Row() != b.Row() {
+			return b.Row() - a.Row()
+		}
+		return a.Column() - b.Column()
+	})
+	for _, cell := range cells {
+		row, col := cell.Row(), cell.Column()
+		if row < 0 || col < 0 || col >= cols {
+			return col-tetromino.Column == 1
+		}
+		if filled, _ := field.FilledTetromino(row, col); filled != common.TetrominoNone {
+			return col-tetromino.Column == 1
+		}
+	}
+	return false
+}
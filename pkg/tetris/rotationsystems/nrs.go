@@ -0,0 +1,65 @@
+package rotationsystems
+
+import (
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// NintendoRotationSystem 任天堂旋转系统（ NRS ），即 NES 版俄罗斯方块使用的旋转系统
+//
+// 方块绕固定的中心旋转，旋转受阻时不踢墙，也不使用方块定义中的踢墙表。不支持 180 度旋转。
+//
+// 参考 https://tetris.wiki/Nintendo_Rotation_System
+type NintendoRotationSystem struct{}
+
+var _ RotationSystem = NintendoRotationSystem{}
+
+// Spawn 返回指定类型的新方块的出生位置
+//
+// 方块出生在场的最上方两行，旋转中心位于中间偏右的一列
+func (NintendoRotationSystem) Spawn(tetrominoType common.TetrominoType, rows, cols int) common.Tetromino {
+	if !isStandard(tetrominoType) {
+		return spawnPiece(tetrominoType, rows, cols, common.ShapesNRS)
+	}
+	col := cols/2 - 1
+	if tetrominoType == common.I {
+		col = cols/2 - 2
+	}
+	return common.Tetromino{
+		Type:   tetrominoType,
+		Row:    rows - 2,
+		Column: col,
+		Dir:    common.Dir0,
+		Shapes: common.ShapesNRS,
+	}
+}
+
+// RotateRight 将场上活跃方块顺时针旋转 90 度
+func (nrs NintendoRotationSystem) RotateRight(field common.MutableField) (Kick, bool) {
+	return nrs.rotate(field, 1)
+}
+
+// RotateLeft 将场上活跃方块逆时针旋转 90 度
+func (nrs NintendoRotationSystem) RotateLeft(field common.MutableField) (Kick, bool) {
+	return nrs.rotate(field, -1)
+}
+
+// Rotate180 不支持，总是返回 ok=false
+func (NintendoRotationSystem) Rotate180(common.MutableField) (Kick, bool) {
+	return Kick{}, false
+}
+
+// rotate 原地旋转
+func (NintendoRotationSystem) rotate(field common.MutableField, dir int) (Kick, bool) {
+	tetromino := field.ActiveTetromino()
+	if tetromino == nil {
+		return Kick{}, false
+	}
+
+	oldDir := tetromino.Dir
+	tetromino.Dir = common.TetrominoDir((int(oldDir) + dir + 4) % 4)
+	if field.IsValid() {
+		return Kick{}, true
+	}
+	tetromino.Dir = oldDir
+	return Kick{}, false
+}
@@ -1,15 +1,60 @@
 package rotationsystems
 
 import (
+	"fmt"
+
 	"github.com/yhlooo/go-tetris/pkg/tetris/common"
 )
 
 // RotationSystem 旋转系统
+//
+// 各旋转方法旋转成功时返回 ok=true 及旋转使用的踢墙。
+// 旋转系统同时决定方块的形状集和出生位置，旋转时不改变方块的形状集
 type RotationSystem interface {
+	// Spawn 返回指定类型的新方块在 rows 行 cols 列的场中的出生位置、方向和形状集
+	Spawn(tetrominoType common.TetrominoType, rows, cols int) common.Tetromino
 	// RotateRight 将场上活跃方块顺时针旋转 90 度
-	RotateRight(field *common.Field) bool
+	RotateRight(field common.MutableField) (kick Kick, ok bool)
 	// RotateLeft 将场上活跃方块逆时针旋转 90 度
-	RotateLeft(field *common.Field) bool
+	RotateLeft(field common.MutableField) (kick Kick, ok bool)
+	// Rotate180 将场上活跃方块旋转 180 度
+	Rotate180(field common.MutableField) (kick Kick, ok bool)
+}
+
+// 内置旋转系统名
+const (
+	// NameSRS 超级旋转系统，现代俄罗斯方块标准
+	NameSRS = "srs"
+	// NameARS Arika 旋转系统， TGM 系列使用
+	NameARS = "ars"
+	// NameNRS 任天堂旋转系统， NES 版本使用
+	NameNRS = "nrs"
+)
+
+// Names 所有内置旋转系统名
+var Names = []string{NameSRS, NameARS, NameNRS}
+
+// New 根据名称创建内置旋转系统
+//
+// name 为空时创建 SRS
+func New(name string) (RotationSystem, error) {
+	switch name {
+	case "", NameSRS:
+		return SuperRotationSystem{}, nil
+	case NameARS:
+		return ArikaRotationSystem{}, nil
+	case NameNRS:
+		return NintendoRotationSystem{}, nil
+	}
+	return nil, fmt.Errorf("unknown rotation system: %q", name)
+}
+
+// Kick 踢墙
+type Kick struct {
+	// 踢墙测试序号，从 0 开始， 0 通常表示未踢墙
+	Index int
+	// 踢墙偏移
+	Offset common.Location
 }
 
 // SuperRotationSystem 超级旋转系统（ SRS ）
@@ -43,36 +88,78 @@ var srsIWallKickData = map[[2]common.TetrominoDir][]common.Location{
 	{common.Dir0, common.DirL}: {{0, 0}, {0, -1}, {0, +2}, {+2, -1}, {-1, +2}},
 }
 
+// srs180WallKickData 180 度旋转踢墙数据
+//
+// 采用 SRS+ 的 180 度踢墙表，参考 https://tetris.wiki/SRS#180.C2.B0_rotation
+var srs180WallKickData = map[[2]common.TetrominoDir][]common.Location{
+	{common.Dir0, common.Dir2}: {{0, 0}, {+1, 0}, {+1, +1}, {+1, -1}, {0, +1}, {0, -1}},
+	{common.Dir2, common.Dir0}: {{0, 0}, {-1, 0}, {-1, -1}, {-1, +1}, {0, -1}, {0, +1}},
+	{common.DirR, common.DirL}: {{0, 0}, {0, +1}, {+2, +1}, {+1, +1}, {+2, 0}, {+1, 0}},
+	{common.DirL, common.DirR}: {{0, 0}, {0, -1}, {+2, -1}, {+1, -1}, {+2, 0}, {+1, 0}},
+}
+
+// Spawn 返回指定类型的新方块的出生位置
+//
+// 放在居中上方刚好露出完整方块的位置
+func (SuperRotationSystem) Spawn(tetrominoType common.TetrominoType, rows, cols int) common.Tetromino {
+	if !isStandard(tetrominoType) {
+		return spawnPiece(tetrominoType, rows, cols, common.ShapesGuideline)
+	}
+	col := cols/2 - 2
+	row := rows - 3
+	if tetrominoType == common.O {
+		col = cols/2 - 1
+		row = rows - 2
+	}
+	return common.Tetromino{
+		Type:   tetrominoType,
+		Row:    row,
+		Column: col,
+		Dir:    common.Dir0,
+		Shapes: common.ShapesGuideline,
+	}
+}
+
 // RotateRight 将场上活跃方块顺时针旋转 90 度
-func (srs SuperRotationSystem) RotateRight(field *common.Field) bool {
+func (srs SuperRotationSystem) RotateRight(field common.MutableField) (Kick, bool) {
 	return srs.rotate(field, 1)
 }
 
 // RotateLeft 将场上活跃方块逆时针旋转 90 度
-func (srs SuperRotationSystem) RotateLeft(field *common.Field) bool {
+func (srs SuperRotationSystem) RotateLeft(field common.MutableField) (Kick, bool) {
 	return srs.rotate(field, -1)
 }
 
+// Rotate180 将场上活跃方块旋转 180 度
+func (srs SuperRotationSystem) Rotate180(field common.MutableField) (Kick, bool) {
+	return srs.rotate(field, 2)
+}
+
 // rotate 旋转
-func (SuperRotationSystem) rotate(field *common.Field, dir int) bool {
+func (SuperRotationSystem) rotate(field common.MutableField, dir int) (Kick, bool) {
 	tetromino := field.ActiveTetromino()
 	if tetromino == nil {
-		return false
+		return Kick{}, false
 	}
 
 	oldDir := tetromino.Dir
-	newDir := common.TetrominoDir(int(oldDir)+dir) % 4
+	newDir := common.TetrominoDir((int(oldDir) + dir + 4) % 4)
 	oldRow := tetromino.Row
 	oldCol := tetromino.Column
 
-	// 确定踢墙数据
-	var wallKickData []common.Location
-	switch tetromino.Type {
-	case common.J, common.L, common.S, common.T, common.Z:
-		wallKickData = srsJLSTZWallKickData[[2]common.TetrominoDir{oldDir, newDir}]
-	case common.I:
+	// 确定踢墙数据，优先使用方块定义中的踢墙表
+	def, _ := common.Piece(tetromino.Type)
+	wallKickData := def.Kick(oldDir, newDir)
+	switch {
+	case wallKickData != nil:
+	case tetromino.Type == common.O:
+	case dir == 2:
+		wallKickData = srs180WallKickData[[2]common.TetrominoDir{oldDir, newDir}]
+	case tetromino.Type == common.I:
 		wallKickData = srsIWallKickData[[2]common.TetrominoDir{oldDir, newDir}]
 	default:
+		// J L S T Z 及未定义踢墙表的其它方块
+		wallKickData = srsJLSTZWallKickData[[2]common.TetrominoDir{oldDir, newDir}]
 	}
 	if wallKickData == nil {
 		wallKickData = []common.Location{{0, 0}}
@@ -80,12 +167,12 @@ func (SuperRotationSystem) rotate(field *common.Field, dir int) bool {
 
 	// 尝试旋转
 	tetromino.Dir = newDir
-	for _, wallKick := range wallKickData {
+	for i, wallKick := range wallKickData {
 		tetromino.Row = oldRow + wallKick.Row()
 		tetromino.Column = oldCol + wallKick.Column()
 		if field.IsValid() {
 			// 旋转成功
-			return true
+			return Kick{Index: i, Offset: wallKick}, true
 		}
 	}
 
@@ -93,5 +180,30 @@ func (SuperRotationSystem) rotate(field *common.Field, dir int) bool {
 	tetromino.Dir = oldDir
 	tetromino.Row = oldRow
 	tetromino.Column = oldCol
-	return false
+	return Kick{}, false
+}
+
+// isStandard 是否为七种标准方块之一
+func isStandard(tetrominoType common.TetrominoType) bool {
+	return tetrominoType >= common.I && tetrominoType <= common.Z
+}
+
+// spawnPiece 返回标准方块以外的方块的出生位置
+//
+// 方块以 Dir0 方向出生在居中上方刚好露出完整方块的位置，宽度与场的宽度奇偶不同时偏左
+func spawnPiece(tetrominoType common.TetrominoType, rows, cols int, shapes common.ShapeSet) common.Tetromino {
+	ret := common.Tetromino{Type: tetrominoType, Dir: common.Dir0, Shapes: shapes}
+	cells := ret.Cells()
+	if len(cells) == 0 {
+		return ret
+	}
+	top, left, right := cells[0].Row(), cells[0].Column(), cells[0].Column()
+	for _, cell := range cells[1:] {
+		top = max(top, cell.Row())
+		left = min(left, cell.Column())
+		right = max(right, cell.Column())
+	}
+	ret.Row = rows - 1 - top
+	ret.Column = (cols-(right-left+1))/2 - left
+	return ret
 }
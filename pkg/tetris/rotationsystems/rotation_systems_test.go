@@ -0,0 +1,299 @@
+package rotationsystems_test
+
+import (
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// newField 创建测试用的场
+//
+// rows 从上往下每行一个字符串， '#' 表示已填充的格子， '.' 表示空格子
+func newField(rows []string, active common.Tetromino) *common.Field {
+	field := common.NewField(len(rows), len(rows[0]), nil)
+	for i, row := range rows {
+		for j := range row {
+			if row[j] == '#' {
+				field.SetTetromino(len(rows)-1-i, j, common.I)
+			}
+		}
+	}
+	field.ChangeActiveTetromino(&active)
+	return field
+}
+
+// emptyRows 返回 n 行 m 列的空场
+func emptyRows(n, m int) []string {
+	rows := make([]string, n)
+	for i := range rows {
+		for j := 0; j < m; j++ {
+			rows[i] += "."
+		}
+	}
+	return rows
+}
+
+// rotationCase 旋转测试用例
+type rotationCase struct {
+	name     string
+	rows     []string
+	active   common.Tetromino
+	rotate   func(field common.MutableField) (rotationsystems.Kick, bool)
+	want     common.Tetromino
+	wantKick int
+	wantOK   bool
+}
+
+// runRotationCases 运行旋转测试用例
+func runRotationCases(t *testing.T, cases []rotationCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			field := newField(c.rows, c.active)
+			if active := field.ActiveTetromino(); active == nil || *active != c.active {
+				t.Fatalf("invalid active tetromino: %+v", c.active)
+			}
+			kick, ok := c.rotate(field)
+			if ok != c.wantOK {
+				t.Errorf("rotate result: %t, expected %t", ok, c.wantOK)
+			}
+			if kick.Index != c.wantKick {
+				t.Errorf("kick index: %d, expected %d", kick.Index, c.wantKick)
+			}
+			if got := *field.ActiveTetromino(); got != c.want {
+				t.Errorf("active tetromino: %+v, expected %+v", got, c.want)
+			}
+		})
+	}
+}
+
+// TestSuperRotationSystem 测试 SRS 旋转及踢墙
+func TestSuperRotationSystem(t *testing.T) {
+	srs := rotationsystems.SuperRotationSystem{}
+	cases := []rotationCase{
+		{
+			name:   "no-kick",
+			rows:   emptyRows(6, 6),
+			active: common.Tetromino{Type: common.T, Row: 2, Column: 2, Dir: common.Dir0},
+			rotate: srs.RotateRight,
+			want:   common.Tetromino{Type: common.T, Row: 2, Column: 2, Dir: common.DirR},
+			wantOK: true,
+		},
+		{
+			// 靠左墙时从 R 转回 0 向右踢一格
+			name:     "wall-kick",
+			rows:     emptyRows(6, 6),
+			active:   common.Tetromino{Type: common.T, Row: 2, Column: -1, Dir: common.DirR},
+			rotate:   srs.RotateLeft,
+			want:     common.Tetromino{Type: common.T, Row: 2, Column: 0, Dir: common.Dir0},
+			wantKick: 1,
+			wantOK:   true,
+		},
+		{
+			// I 从 0 转到 R 时第二个踢墙位置向左两格
+			name: "i-kick",
+			rows: []string{
+				"......",
+				"......",
+				"......",
+				"......",
+				"....#.",
+				"......",
+			},
+			active:   common.Tetromino{Type: common.I, Row: 1, Column: 2, Dir: common.Dir0},
+			rotate:   srs.RotateRight,
+			want:     common.Tetromino{Type: common.I, Row: 1, Column: 0, Dir: common.DirR},
+			wantKick: 1,
+			wantOK:   true,
+		},
+		{
+			name: "blocked",
+			rows: []string{
+				"####",
+				"....",
+				"####",
+			},
+			active: common.Tetromino{Type: common.I, Row: 0, Column: 0, Dir: common.Dir2},
+			rotate: srs.RotateRight,
+			want:   common.Tetromino{Type: common.I, Row: 0, Column: 0, Dir: common.Dir2},
+			wantOK: false,
+		},
+		{
+			name:   "180-no-kick",
+			rows:   emptyRows(6, 6),
+			active: common.Tetromino{Type: common.T, Row: 2, Column: 2, Dir: common.Dir0},
+			rotate: srs.Rotate180,
+			want:   common.Tetromino{Type: common.T, Row: 2, Column: 2, Dir: common.Dir2},
+			wantOK: true,
+		},
+		{
+			// 贴地时从 0 转到 2 向上踢一格
+			name:     "180-floor-kick",
+			rows:     emptyRows(6, 6),
+			active:   common.Tetromino{Type: common.T, Row: -1, Column: 2, Dir: common.Dir0},
+			rotate:   srs.Rotate180,
+			want:     common.Tetromino{Type: common.T, Row: 0, Column: 2, Dir: common.Dir2},
+			wantKick: 1,
+			wantOK:   true,
+		},
+		{
+			// 从 R 转到 L 时前四个位置均被阻挡，踢到第五个位置（上移两格）
+			name: "180-side-kick",
+			rows: []string{
+				"......",
+				"......",
+				"......",
+				"..#...",
+				"#..#..",
+				"#.###.",
+			},
+			active:   common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.DirR},
+			rotate:   srs.Rotate180,
+			want:     common.Tetromino{Type: common.T, Row: 2, Column: 0, Dir: common.DirL},
+			wantKick: 4,
+			wantOK:   true,
+		},
+		{
+			name:   "180-o",
+			rows:   emptyRows(4, 4),
+			active: common.Tetromino{Type: common.O, Row: 0, Column: 1, Dir: common.Dir0},
+			rotate: srs.Rotate180,
+			want:   common.Tetromino{Type: common.O, Row: 0, Column: 1, Dir: common.Dir2},
+			wantOK: true,
+		},
+	}
+	runRotationCases(t, cases)
+}
+
+// TestArikaRotationSystem 测试 ARS 旋转及踢墙
+func TestArikaRotationSystem(t *testing.T) {
+	ars := rotationsystems.ArikaRotationSystem{}
+	tetromino := func(tetrominoType common.TetrominoType, row, col int, dir common.TetrominoDir) common.Tetromino {
+		return common.Tetromino{Type: tetrominoType, Row: row, Column: col, Dir: dir, Shapes: common.ShapesARS}
+	}
+	runRotationCases(t, []rotationCase{
+		{
+			name:   "no-kick",
+			rows:   emptyRows(6, 6),
+			active: tetromino(common.T, 2, 2, common.Dir0),
+			rotate: ars.RotateRight,
+			want:   tetromino(common.T, 2, 2, common.DirR),
+			wantOK: true,
+		},
+		{
+			// 靠左墙时从 L 转回 0 向右踢一格
+			name:     "wall-kick",
+			rows:     emptyRows(6, 6),
+			active:   tetromino(common.T, 2, -1, common.DirL),
+			rotate:   ars.RotateRight,
+			want:     tetromino(common.T, 2, 0, common.Dir0),
+			wantKick: 1,
+			wantOK:   true,
+		},
+		{
+			// 第一个受阻的格子位于中间列，不踢墙
+			name: "center-column",
+			rows: []string{
+				"......",
+				"......",
+				"......",
+				".#....",
+				"......",
+				"......",
+			},
+			active: tetromino(common.T, 0, 0, common.Dir0),
+			rotate: ars.RotateRight,
+			want:   tetromino(common.T, 0, 0, common.Dir0),
+			wantOK: false,
+		},
+		{
+			// I 方块不踢墙
+			name:   "i-no-kick",
+			rows:   emptyRows(6, 4),
+			active: tetromino(common.I, 0, 1, common.DirR),
+			rotate: ars.RotateLeft,
+			want:   tetromino(common.I, 0, 1, common.DirR),
+			wantOK: false,
+		},
+		{
+			name:   "no-180",
+			rows:   emptyRows(6, 6),
+			active: tetromino(common.T, 2, 2, common.Dir0),
+			rotate: ars.Rotate180,
+			want:   tetromino(common.T, 2, 2, common.Dir0),
+			wantOK: false,
+		},
+	})
+}
+
+// TestNintendoRotationSystem 测试 NRS 旋转
+func TestNintendoRotationSystem(t *testing.T) {
+	nrs := rotationsystems.NintendoRotationSystem{}
+	tetromino := func(tetrominoType common.TetrominoType, row, col int, dir common.TetrominoDir) common.Tetromino {
+		return common.Tetromino{Type: tetrominoType, Row: row, Column: col, Dir: dir, Shapes: common.ShapesNRS}
+	}
+	runRotationCases(t, []rotationCase{
+		{
+			name:   "rotate",
+			rows:   emptyRows(6, 6),
+			active: tetromino(common.T, 2, 2, common.Dir0),
+			rotate: nrs.RotateLeft,
+			want:   tetromino(common.T, 2, 2, common.DirL),
+			wantOK: true,
+		},
+		{
+			// 靠左墙时旋转受阻，不踢墙
+			name:   "no-kick",
+			rows:   emptyRows(6, 6),
+			active: tetromino(common.T, 2, -1, common.DirL),
+			rotate: nrs.RotateRight,
+			want:   tetromino(common.T, 2, -1, common.DirL),
+			wantOK: false,
+		},
+		{
+			name:   "no-180",
+			rows:   emptyRows(6, 6),
+			active: tetromino(common.T, 2, 2, common.Dir0),
+			rotate: nrs.Rotate180,
+			want:   tetromino(common.T, 2, 2, common.Dir0),
+			wantOK: false,
+		},
+	})
+}
+
+// TestSpawn 测试各旋转系统的出生位置
+func TestSpawn(t *testing.T) {
+	cases := []struct {
+		name          string
+		tetrominoType common.TetrominoType
+		want          common.Tetromino
+	}{
+		{name: rotationsystems.NameARS, tetrominoType: common.T, want: common.Tetromino{Type: common.T, Row: 18, Column: 3, Shapes: common.ShapesARS}},
+		{name: rotationsystems.NameARS, tetrominoType: common.I, want: common.Tetromino{Type: common.I, Row: 17, Column: 3, Shapes: common.ShapesARS}},
+		{name: rotationsystems.NameNRS, tetrominoType: common.T, want: common.Tetromino{Type: common.T, Row: 18, Column: 4, Shapes: common.ShapesNRS}},
+		{name: rotationsystems.NameNRS, tetrominoType: common.I, want: common.Tetromino{Type: common.I, Row: 18, Column: 3, Shapes: common.ShapesNRS}},
+	}
+	for _, c := range cases {
+		t.Run(c.name+"/"+c.tetrominoType.String(), func(t *testing.T) {
+			rs, err := rotationsystems.New(c.name)
+			if err != nil {
+				t.Fatalf("new rotation system error: %v", err)
+			}
+			got := rs.Spawn(c.tetrominoType, 20, 10)
+			if got != c.want {
+				t.Errorf("spawn: %+v, expected %+v", got, c.want)
+			}
+			// 出生的方块完全位于场内最上方两行
+			for _, cell := range got.Cells() {
+				if cell.Row() < 18 || cell.Row() >= 20 || cell.Column() < 0 || cell.Column() >= 10 {
+					t.Errorf("cell %v out of top two rows", cell)
+				}
+			}
+		})
+	}
+
+	if _, err := rotationsystems.New("unknown"); err == nil {
+		t.Errorf("expected unknown rotation system error")
+	}
+}
@@ -0,0 +1,58 @@
+package tetris_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/randomizer"
+)
+
+// pieceSequence 返回游戏当前活跃方块及后续方块
+func pieceSequence(frame tetris.Frame) []common.TetrominoType {
+	return append([]common.TetrominoType{frame.Field.ActiveTetromino().Type}, frame.NextTetrominoes...)
+}
+
+// TestSeed 测试相同种子生成相同的方块序列
+func TestSeed(t *testing.T) {
+	cases := []struct {
+		name       string
+		seed       uint64
+		randomizer string
+	}{
+		{name: "7bag", seed: 42},
+		{name: "tgm", seed: 42, randomizer: randomizer.NameTGM},
+		{name: "random-seed", seed: 0, randomizer: randomizer.NameNES},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = c.seed
+			opts.RandomizerName = c.randomizer
+			frame := newManualGame(t, opts).CurrentFrame()
+			if c.seed != 0 && frame.Seed != c.seed {
+				t.Errorf("seed: %d, expected %d", frame.Seed, c.seed)
+			}
+			if frame.Seed == 0 {
+				t.Fatalf("seed is 0")
+			}
+
+			// 使用帧中的种子可重新开始相同的游戏
+			opts.Seed = frame.Seed
+			again := newManualGame(t, opts).CurrentFrame()
+			if got, want := pieceSequence(again), pieceSequence(frame); !slices.Equal(got, want) {
+				t.Errorf("pieces: %v, expected %v", got, want)
+			}
+
+			// 种子随存档保存
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			if got := restoreManualGame(t, snapshot).CurrentFrame().Seed; got != frame.Seed {
+				t.Errorf("restored seed: %d, expected %d", got, frame.Seed)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package tetris
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// SnapshotVersion 当前存档格式版本
+const SnapshotVersion = 1
+
+// emptyCellChar 存档中表示空格子的字符
+const emptyCellChar = '.'
+
+// Snapshot 游戏存档
+//
+// 包含恢复一局进行中的游戏所需的全部状态，可序列化为 JSON
+type Snapshot struct {
+	// 存档格式版本
+	Version int `json:"version"`
+	// 随机种子
+	Seed uint64 `json:"seed"`
+	// 游戏选项
+	Options SavedOptions `json:"options"`
+
+	// 场上已填充方块（含隐藏行），从下往上每行一个字符串，每个字符表示一格
+	//
+	// 内置方块用方块名的首字母表示，其它方块用 customCellChars 中的字符表示，第 i 个字符对应 Pieces[i]
+	Field []string `json:"field"`
+	// 场上非内置方块的方块名表
+	Pieces []string `json:"pieces,omitempty"`
+	// 当前活跃方块
+	ActiveTetromino common.Tetromino `json:"activeTetromino"`
+	// 暂存的方块
+	HoldingTetromino *common.TetrominoType `json:"holdingTetromino,omitempty"`
+	// 下几个方块
+	NextTetrominoes []common.TetrominoType `json:"nextTetrominoes"`
+	// 随机生成器状态
+	Randomizer []byte `json:"randomizer"`
+
+	Level       int  `json:"level"`
+	Score       int  `json:"score"`
+	ClearLines  int  `json:"clearLines"`
+	BackToBack  bool `json:"backToBack"`
+	ClearStreak int  `json:"clearStreak,omitempty"`
+	// 统计数据
+	Stats Stats `json:"stats"`
+
+	Holed              bool  `json:"holed"`
+	NotMove            bool  `json:"notMove"`
+	TSTKick            bool  `json:"tstKick,omitempty"`
+	PieceInputs        int   `json:"pieceInputs,omitempty"`
+	PieceSoftDropping  bool  `json:"pieceSoftDropping,omitempty"`
+	Tickets            int64 `json:"tickets"`
+	FallDownTickets    int64 `json:"fallDownTickets"`
+	LockDownTickets    int64 `json:"lockDownTickets"`
+	LockDownResetTimes int   `json:"lockDownResetTimes"`
+	// 当前方块到达过的最低行
+	LowestRow int `json:"lowestRow"`
+
+	// 按住的按键和自动重复移动计数
+	LeftHeld     bool  `json:"leftHeld,omitempty"`
+	RightHeld    bool  `json:"rightHeld,omitempty"`
+	ShiftDir     int   `json:"shiftDir,omitempty"`
+	DASTickets   int64 `json:"dasTickets,omitempty"`
+	ARRTickets   int64 `json:"arrTickets,omitempty"`
+	SoftDropHeld bool  `json:"softDropHeld,omitempty"`
+}
+
+// UnmarshalSnapshot 从 JSON 解析存档
+//
+// 存档格式版本不受支持时返回错误
+func UnmarshalSnapshot(data []byte) (Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("unmarshal snapshot error: %w", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return snapshot, fmt.Errorf("unsupported snapshot version: %d", snapshot.Version)
+	}
+	return snapshot, nil
+}
+
+// RestoreTetris 从存档恢复 Tetris 游戏实例
+//
+// opts 用于提供存档中未记录的选项（如评分器、自定义的旋转系统、日志等），存档中记录的选项、随机种子会覆盖 opts 中对应的值。
+// 若 opts 中指定了随机生成器，其需实现 encoding.BinaryUnmarshaler 以恢复状态。
+// 恢复的游戏处于 StatePending 状态，需调用 Start 继续；恢复的游戏不支持录像
+func RestoreTetris(snapshot Snapshot, opts Options) (Tetris, error) {
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version: %d", snapshot.Version)
+	}
+
+	snapshot.Options.ApplyTo(&opts)
+	opts.Seed = snapshot.Seed
+	opts.Recorder = nil
+	opts.Complete()
+
+	t := newDefaultTetris(opts)
+
+	// 恢复随机生成器
+	u, ok := t.randomizer.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return nil, fmt.Errorf("randomizer %T can not be restored", t.randomizer)
+	}
+	if err := u.UnmarshalBinary(snapshot.Randomizer); err != nil {
+		return nil, fmt.Errorf("restore randomizer error: %w", err)
+	}
+
+	// 恢复场
+	rows := opts.Rows + opts.HiddenRows
+	if len(snapshot.Field) != rows {
+		return nil, fmt.Errorf("invalid field rows: %d (expected %d)", len(snapshot.Field), rows)
+	}
+	active := snapshot.ActiveTetromino
+	t.field = common.NewBitField(rows, opts.Columns, nil)
+	pieces := make([]common.TetrominoType, len(snapshot.Pieces))
+	for i, name := range snapshot.Pieces {
+		tetrominoType, ok := common.LookupPiece(name)
+		if !ok || !tetrominoType.IsPiece() {
+			return nil, fmt.Errorf("unknown piece in field: %q", name)
+		}
+		pieces[i] = tetrominoType
+	}
+	for i, row := range snapshot.Field {
+		if len(row) != opts.Columns {
+			return nil, fmt.Errorf("invalid field row %d length: %d (expected %d)", i, len(row), opts.Columns)
+		}
+		for j := 0; j < len(row); j++ {
+			tetrominoType, ok := decodeCell(row[j], pieces)
+			if !ok {
+				return nil, fmt.Errorf("invalid field cell (%d, %d): %q", i, j, row[j])
+			}
+			t.field.SetTetromino(i, j, tetrominoType)
+		}
+	}
+	if !t.field.ChangeActiveTetromino(&active) {
+		return nil, fmt.Errorf("invalid active tetromino: %+v", active)
+	}
+
+	if len(snapshot.NextTetrominoes) != opts.ShowNextTetrominoes+1 {
+		return nil, fmt.Errorf(
+			"invalid next tetrominoes count: %d (expected %d)",
+			len(snapshot.NextTetrominoes), opts.ShowNextTetrominoes+1,
+		)
+	}
+	t.nextTetrominoes = append([]common.TetrominoType(nil), snapshot.NextTetrominoes...)
+	if snapshot.HoldingTetromino != nil {
+		holding := *snapshot.HoldingTetromino
+		t.holdingTetromino = &holding
+	}
+
+	t.level = snapshot.Level
+	t.score = snapshot.Score
+	t.clearLines = snapshot.ClearLines
+	t.backToBack = snapshot.BackToBack
+	t.clearStreak = snapshot.ClearStreak
+	t.stats = snapshot.Stats.clone()
+	t.holed = snapshot.Holed
+	t.notMove = snapshot.NotMove
+	t.tstKick = snapshot.TSTKick
+	t.pieceInputs = snapshot.PieceInputs
+	t.pieceSoftDropping = snapshot.PieceSoftDropping
+	t.tickets = snapshot.Tickets
+	t.fallDownTickets = snapshot.FallDownTickets
+	t.lockDownTickets = snapshot.LockDownTickets
+	t.lockDownResetTimes = snapshot.LockDownResetTimes
+	t.lowestRow = snapshot.LowestRow
+	t.leftHeld = snapshot.LeftHeld
+	t.rightHeld = snapshot.RightHeld
+	t.shiftDir = snapshot.ShiftDir
+	t.dasTickets = snapshot.DASTickets
+	t.arrTickets = snapshot.ARRTickets
+	t.softDropHeld = snapshot.SoftDropHeld
+
+	return t, nil
+}
+
+// Snapshot 创建游戏存档
+func (t *defaultTetris) Snapshot() (Snapshot, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.state == StateFinished {
+		return Snapshot{}, fmt.Errorf("game already finished")
+	}
+
+	m, ok := t.randomizer.(encoding.BinaryMarshaler)
+	if !ok {
+		return Snapshot{}, fmt.Errorf("randomizer %T can not be saved", t.randomizer)
+	}
+	randomizerData, err := m.MarshalBinary()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("save randomizer error: %w", err)
+	}
+
+	field := make([]string, t.rows+t.hiddenRows)
+	var pieces []common.TetrominoType
+	for i := range field {
+		row := make([]byte, t.cols)
+		for j := range row {
+			tetrominoType, _ := t.field.FilledTetromino(i, j)
+			if row[j], pieces = encodeCell(tetrominoType, pieces); row[j] == 0 {
+				return Snapshot{}, fmt.Errorf("too many kinds of pieces in field")
+			}
+		}
+		field[i] = string(row)
+	}
+	var pieceNames []string
+	for _, p := range pieces {
+		pieceNames = append(pieceNames, p.String())
+	}
+
+	snapshot := Snapshot{
+		Version: SnapshotVersion,
+		Seed:    t.seed,
+		Options: t.opts,
+
+		Field:           field,
+		Pieces:          pieceNames,
+		ActiveTetromino: *t.field.ActiveTetromino(),
+		NextTetrominoes: append([]common.TetrominoType(nil), t.nextTetrominoes...),
+		Randomizer:      randomizerData,
+
+		Level:       t.level,
+		Score:       t.score,
+		ClearLines:  t.clearLines,
+		BackToBack:  t.backToBack,
+		ClearStreak: t.clearStreak,
+		Stats:       t.stats.clone(),
+
+		Holed:              t.holed,
+		NotMove:            t.notMove,
+		TSTKick:            t.tstKick,
+		PieceInputs:        t.pieceInputs,
+		PieceSoftDropping:  t.pieceSoftDropping,
+		Tickets:            t.tickets,
+		FallDownTickets:    t.fallDownTickets,
+		LockDownTickets:    t.lockDownTickets,
+		LockDownResetTimes: t.lockDownResetTimes,
+		LowestRow:          t.lowestRow,
+
+		LeftHeld:     t.leftHeld,
+		RightHeld:    t.rightHeld,
+		ShiftDir:     t.shiftDir,
+		DASTickets:   t.dasTickets,
+		ARRTickets:   t.arrTickets,
+		SoftDropHeld: t.softDropHeld,
+	}
+	if t.holdingTetromino != nil {
+		holding := *t.holdingTetromino
+		snapshot.HoldingTetromino = &holding
+	}
+
+	return snapshot, nil
+}
+
+// customCellChars 存档中表示非内置方块的字符，按在场中出现的顺序依次分配
+const customCellChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// encodeCell 将格子中的方块类型编码为存档中的字符
+//
+// 内置方块使用方块名的首字母，其它方块使用 customCellChars 中与其在 pieces 中的下标对应的字符，不在 pieces 中时追加到 pieces 末尾。
+// 返回编码的字符和更新后的 pieces ， customCellChars 中的字符不够用时返回的字符为 0
+func encodeCell(tetrominoType common.TetrominoType, pieces []common.TetrominoType) (byte, []common.TetrominoType) {
+	switch {
+	case tetrominoType == common.TetrominoNone:
+		return emptyCellChar, pieces
+	case tetrominoType <= common.Garbage:
+		return tetrominoType.String()[0], pieces
+	}
+	i := slices.Index(pieces, tetrominoType)
+	if i < 0 {
+		i = len(pieces)
+		pieces = append(pieces, tetrominoType)
+	}
+	if i >= len(customCellChars) {
+		return 0, pieces
+	}
+	return customCellChars[i], pieces
+}
+
+// decodeCell 将存档中的字符解码为方块类型
+//
+// pieces 为存档中非内置方块的方块名表对应的方块类型
+func decodeCell(c byte, pieces []common.TetrominoType) (common.TetrominoType, bool) {
+	if c == emptyCellChar {
+		return common.TetrominoNone, true
+	}
+	for t := common.I; t <= common.Garbage; t++ {
+		if t.String()[0] == c {
+			return t, true
+		}
+	}
+	if i := strings.IndexByte(customCellChars, c); i >= 0 && i < len(pieces) {
+		return pieces[i], true
+	}
+	return common.TetrominoNone, false
+}
@@ -0,0 +1,157 @@
+package tetris_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+)
+
+// TestSnapshotRoundTrip 测试存档经 JSON 序列化后恢复的游戏与原游戏的后续过程相同
+func TestSnapshotRoundTrip(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(opts *tetris.Options)
+	}{
+		{name: "default", modify: func(*tetris.Options) {}},
+		{name: "no-hold-no-reset", modify: func(opts *tetris.Options) {
+			opts.HoldEnabled = false
+			opts.LockDownPolicy = tetris.LockDownStepReset
+		}},
+		{name: "wide", modify: func(opts *tetris.Options) {
+			opts.Rows = 24
+			opts.Columns = 16
+		}},
+		{name: "pentomino", modify: func(opts *tetris.Options) {
+			opts.PieceSet = pieces.NamePentomino
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 3
+			c.modify(&opts)
+			game := newManualGame(t, opts)
+			script := newScript(4, 200, true)
+			runScript(t, game, script[:20])
+			if game.State() != tetris.StateRunning {
+				t.Fatalf("game not running after first steps: %s", game.State())
+			}
+
+			snapshot, err := game.Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			data, err := json.Marshal(snapshot)
+			if err != nil {
+				t.Fatalf("marshal snapshot error: %v", err)
+			}
+			snapshot, err = tetris.UnmarshalSnapshot(data)
+			if err != nil {
+				t.Fatalf("unmarshal snapshot error: %v", err)
+			}
+			restored := restoreManualGame(t, snapshot)
+
+			if got, want := restored.CurrentFrame(), game.CurrentFrame(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("restored frame mismatch:\n got: %+v\nwant: %+v", got, want)
+			}
+			want := runScript(t, game, script[20:])
+			got := runScript(t, restored, script[20:])
+			if len(got) != len(want) {
+				t.Fatalf("frames count mismatch: %d != %d", len(got), len(want))
+			}
+			for i := range want {
+				if !reflect.DeepEqual(got[i], want[i]) {
+					t.Fatalf("frame %d mismatch:\n got: %+v\nwant: %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSnapshotCustomPieces 测试存档中的非内置方块以方块名表记录
+func TestSnapshotCustomPieces(t *testing.T) {
+	opts := tetris.DefaultOptions
+	opts.Seed = 5
+	opts.PieceSet = pieces.NamePentomino
+	game := newManualGame(t, opts)
+	runScript(t, game, newScript(6, 30, false))
+
+	snapshot, err := game.Snapshot()
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if len(snapshot.Pieces) == 0 {
+		t.Fatalf("no pieces in snapshot, field: %v", snapshot.Field)
+	}
+	counts := map[byte]int{}
+	for _, row := range snapshot.Field {
+		for j := range row {
+			counts[row[j]]++
+		}
+	}
+	for i, name := range snapshot.Pieces {
+		if _, ok := common.LookupPiece(name); !ok {
+			t.Errorf("unknown piece name %q", name)
+		}
+		// 方块名表中的方块按出现顺序依次用 'a' 、 'b' ……表示
+		if c := byte('a' + i); counts[c] == 0 {
+			t.Errorf("piece %q (%q) not in field", name, c)
+		}
+	}
+
+	// 方块名表中的方块未注册时无法恢复
+	snapshot.Pieces[0] = "unknown"
+	if _, err := tetris.RestoreTetris(snapshot, tetris.DefaultOptions); err == nil {
+		t.Errorf("expected unknown piece error")
+	}
+}
+
+// TestUnmarshalSnapshotUnsupportedVersion 测试不支持的存档版本返回错误
+func TestUnmarshalSnapshotUnsupportedVersion(t *testing.T) {
+	cases := []struct {
+		version int
+		wantErr bool
+	}{
+		{version: 0, wantErr: true},
+		{version: tetris.SnapshotVersion, wantErr: false},
+		{version: tetris.SnapshotVersion + 1, wantErr: true},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(tetris.Snapshot{Version: c.version})
+		if err != nil {
+			t.Fatalf("marshal snapshot error: %v", err)
+		}
+		if _, err := tetris.UnmarshalSnapshot(data); (err != nil) != c.wantErr {
+			t.Errorf("version %d: error %v, expected error: %t", c.version, err, c.wantErr)
+		}
+	}
+}
+
+// restoreManualGame 从存档恢复使用 ManualClock 的游戏并开始
+func restoreManualGame(t *testing.T, snapshot tetris.Snapshot) tetris.Tetris {
+	t.Helper()
+	opts := tetris.DefaultOptions
+	opts.Clock = tetris.ManualClock{}
+	game, err := tetris.RestoreTetris(snapshot, opts)
+	if err != nil {
+		t.Fatalf("restore game error: %v", err)
+	}
+	if err := game.Start(t.Context()); err != nil {
+		t.Fatalf("start restored game error: %v", err)
+	}
+	t.Cleanup(func() { _ = game.Stop() })
+	return game
+}
+
+// setSnapshotField 设置存档场最底部的若干行
+//
+// rows 从上往下每行一个字符串，格式同 Snapshot.Field
+func setSnapshotField(snapshot *tetris.Snapshot, rows ...string) {
+	for i, row := range rows {
+		snapshot.Field[len(rows)-1-i] = row
+	}
+}
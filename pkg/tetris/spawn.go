@@ -0,0 +1,38 @@
+package tetris
+
+import (
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// SpawnTetromino 返回指定类型的新方块在场 field 中的出生位置，与游戏中新方块出生的规则一致
+//
+// field 包含可见行之上的隐藏行， visibleRows 为可见行数（见 Frame.VisibleRows ）。
+// 方块在可见行之上 min(隐藏行数, 2) 行处出生，有隐藏行且下方没有阻挡时立即下落一格。
+// field 中的活跃方块会被忽略。出生位置被阻挡时返回 ok=false
+func SpawnTetromino(
+	field *common.BitField,
+	rs rotationsystems.RotationSystem,
+	tetrominoType common.TetrominoType,
+	visibleRows int,
+) (tetromino common.Tetromino, ok bool) {
+	rows, cols := field.Size()
+	hiddenRows := max(rows-visibleRows, 0)
+	tetromino = rs.Spawn(tetrominoType, spawnRows(visibleRows, hiddenRows), cols)
+	if field.Collides(tetromino) {
+		return tetromino, false
+	}
+	if hiddenRows > 0 {
+		dropped := tetromino
+		dropped.Row--
+		if !field.Collides(dropped) {
+			tetromino = dropped
+		}
+	}
+	return tetromino, true
+}
+
+// spawnRows 返回计算出生位置时使用的行数，即可见行数加上最多 2 行隐藏行
+func spawnRows(visibleRows, hiddenRows int) int {
+	return visibleRows + min(hiddenRows, 2)
+}
@@ -0,0 +1,155 @@
+package tetris
+
+import (
+	"maps"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// comboAttack 连击数对应的攻击行数，超出部分按最后一项计算
+var comboAttack = []int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4, 5}
+
+// Stats 游戏统计数据
+type Stats struct {
+	// 已放置的方块数
+	Pieces int `json:"pieces"`
+	// 各类型方块放置数，含自定义方块，序列化为以方块名为键的 JSON 对象
+	PieceCounts map[common.TetrominoType]int `json:"pieceCounts"`
+	// 操作数（不含松开按键）
+	Inputs int `json:"inputs"`
+	// 攻击行数
+	Attack int `json:"attack"`
+
+	// 各类消行次数（按清除行数分类，含 T-Spin 消行）
+	Singles  int `json:"singles"`
+	Doubles  int `json:"doubles"`
+	Triples  int `json:"triples"`
+	Tetrises int `json:"tetrises"`
+	// 一次消除 5 行及以上的次数（自定义方块）
+	FivePlusClears int `json:"fivePlusClears,omitempty"`
+	// T-Spin 消行次数（含 T-Spin Mini）
+	TSpins int `json:"tSpins"`
+	// 全消次数
+	PerfectClears int `json:"perfectClears"`
+	// 最大连击数
+	MaxCombo int `json:"maxCombo"`
+	// Finesse 错误数，即各方块实际操作数比最少操作数多出的部分之和
+	FinesseFaults int `json:"finesseFaults"`
+
+	// 游戏已进行的时间（不含暂停时间）
+	Elapsed time.Duration `json:"-"`
+}
+
+// PPS 返回每秒放置方块数 (Pieces Per Second)
+func (s Stats) PPS() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Pieces) / s.Elapsed.Seconds()
+}
+
+// KPP 返回平均每个方块的操作数 (Keys Per Piece)
+func (s Stats) KPP() float64 {
+	if s.Pieces == 0 {
+		return 0
+	}
+	return float64(s.Inputs) / float64(s.Pieces)
+}
+
+// APM 返回每分钟攻击行数 (Attack Per Minute)
+func (s Stats) APM() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Attack) / s.Elapsed.Minutes()
+}
+
+// clone 返回不与 s 共享 PieceCounts 的副本
+func (s Stats) clone() Stats {
+	s.PieceCounts = maps.Clone(s.PieceCounts)
+	return s
+}
+
+// addLockDown 记录一次方块锁定
+func (s *Stats) addLockDown(
+	tetrominoType common.TetrominoType,
+	tSpin common.TSpinType,
+	clearLines int,
+	perfectClear bool,
+	backToBack bool,
+	combo int,
+) {
+	s.Pieces++
+	if tetrominoType.IsPiece() {
+		if s.PieceCounts == nil {
+			s.PieceCounts = map[common.TetrominoType]int{}
+		}
+		s.PieceCounts[tetrominoType]++
+	}
+
+	switch {
+	case clearLines == 1:
+		s.Singles++
+	case clearLines == 2:
+		s.Doubles++
+	case clearLines == 3:
+		s.Triples++
+	case clearLines == 4:
+		s.Tetrises++
+	case clearLines >= 5:
+		s.FivePlusClears++
+	}
+	if clearLines > 0 && tSpin != common.TSpinNone {
+		s.TSpins++
+	}
+	if perfectClear {
+		s.PerfectClears++
+	}
+	s.MaxCombo = max(s.MaxCombo, combo)
+	s.Attack += attackLines(tSpin, clearLines, perfectClear, backToBack, combo)
+}
+
+// AttackLines 计算得分事件对应的攻击行数，非消行事件为 0
+func AttackLines(event ScoreEvent) int {
+	return attackLines(event.TSpin, event.ClearLines, event.PerfectClear, event.BackToBack, event.Combo)
+}
+
+// attackLines 计算一次消行的攻击行数
+//
+// backToBack 表示上一次消行是否为困难消行
+func attackLines(tSpin common.TSpinType, clearLines int, perfectClear bool, backToBack bool, combo int) int {
+	if clearLines == 0 {
+		return 0
+	}
+
+	attack := 0
+	difficult := false
+	switch tSpin {
+	case common.TSpinFull:
+		attack = 2 * clearLines
+		difficult = true
+	case common.TSpinMini:
+		attack = clearLines - 1
+		difficult = true
+	default:
+		switch {
+		case clearLines == 2:
+			attack = 1
+		case clearLines == 3:
+			attack = 2
+		case clearLines >= 4:
+			// Tetris 及自定义方块一次消除 5 行及以上，每行攻击 1 行
+			attack = clearLines
+			difficult = true
+		}
+	}
+	if backToBack && difficult {
+		attack++
+	}
+	attack += comboAttack[min(combo, len(comboAttack)-1)]
+	if perfectClear {
+		attack += 10
+	}
+	return attack
+}
@@ -0,0 +1,169 @@
+package tetris_test
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+)
+
+// TestStatsRates 测试统计数据的速率计算
+func TestStatsRates(t *testing.T) {
+	stats := tetris.Stats{Pieces: 30, Inputs: 90, Attack: 20, Elapsed: 20 * time.Second}
+	if got := stats.PPS(); got != 1.5 {
+		t.Errorf("PPS: %v, expected 1.5", got)
+	}
+	if got := stats.KPP(); got != 3 {
+		t.Errorf("KPP: %v, expected 3", got)
+	}
+	if got := stats.APM(); got != 60 {
+		t.Errorf("APM: %v, expected 60", got)
+	}
+
+	// 未开始时不除以 0
+	empty := tetris.Stats{}
+	if empty.PPS() != 0 || empty.KPP() != 0 || empty.APM() != 0 {
+		t.Errorf("empty stats rates: %v %v %v, expected all 0", empty.PPS(), empty.KPP(), empty.APM())
+	}
+}
+
+// TestStatsLockDown 测试锁定方块后统计数据的更新
+func TestStatsLockDown(t *testing.T) {
+	i5, _ := common.LookupPiece("I5")
+	cases := []struct {
+		name        string
+		rows        []string
+		pieceSet    string
+		active      common.Tetromino
+		ops         []tetris.Op
+		backToBack  bool
+		clearStreak int
+		want        tetris.Stats
+	}{
+		{
+			name:   "t-spin-double",
+			active: common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.DirR},
+			ops:    []tetris.Op{tetris.OpRotateRight, tetris.OpHardDrop},
+			want: tetris.Stats{
+				Pieces:      1,
+				PieceCounts: map[common.TetrominoType]int{common.T: 1},
+				Inputs:      2,
+				Attack:      4,
+				Doubles:     1,
+				TSpins:      1,
+			},
+		},
+		{
+			// Back-to-Back 和连击额外增加攻击
+			name:        "back-to-back-combo",
+			active:      common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.DirR},
+			ops:         []tetris.Op{tetris.OpRotateRight, tetris.OpHardDrop},
+			backToBack:  true,
+			clearStreak: 2,
+			want: tetris.Stats{
+				Pieces:      1,
+				PieceCounts: map[common.TetrominoType]int{common.T: 1},
+				Inputs:      2,
+				Attack:      4 + 1 + 1,
+				Doubles:     1,
+				TSpins:      1,
+				MaxCombo:    2,
+			},
+		},
+		{
+			// 松开按键不计入操作数
+			name:   "double",
+			active: common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			ops:    []tetris.Op{tetris.OpMoveLeft, tetris.OpMoveLeftRelease, tetris.OpHardDrop},
+			want: tetris.Stats{
+				Pieces:      1,
+				PieceCounts: map[common.TetrominoType]int{common.T: 1},
+				Inputs:      2,
+				Attack:      1,
+				Doubles:     1,
+			},
+		},
+		{
+			// 自定义方块一次消除 5 行且全消
+			name:     "five-lines",
+			rows:     []string{".GGGGGGGGG", ".GGGGGGGGG", ".GGGGGGGGG", ".GGGGGGGGG", ".GGGGGGGGG"},
+			pieceSet: pieces.NamePentomino,
+			active:   common.Tetromino{Type: i5, Row: 0, Column: -2, Dir: common.DirR},
+			ops:      []tetris.Op{tetris.OpHardDrop},
+			want: tetris.Stats{
+				Pieces:         1,
+				PieceCounts:    map[common.TetrominoType]int{i5: 1},
+				Inputs:         1,
+				Attack:         5 + 10,
+				FivePlusClears: 1,
+				PerfectClears:  1,
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.PieceSet = c.pieceSet
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			if c.rows == nil {
+				c.rows = []string{
+					"G.........",
+					"...GGGGGGG",
+					"G.GGGGGGGG",
+				}
+			}
+			setSnapshotField(&snapshot, c.rows...)
+			snapshot.ActiveTetromino = c.active
+			snapshot.BackToBack = c.backToBack
+			snapshot.ClearStreak = c.clearStreak
+			game := restoreManualGame(t, snapshot)
+			for _, op := range c.ops {
+				game.Input(op)
+			}
+
+			got := game.CurrentFrame().Stats
+			got.Elapsed = 0
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("stats: %+v, expected %+v", got, c.want)
+			}
+		})
+	}
+}
+
+// TestStatsJSON 测试统计数据中各类型方块放置数以方块名为键序列化
+func TestStatsJSON(t *testing.T) {
+	stats := tetris.Stats{
+		Pieces:      3,
+		PieceCounts: map[common.TetrominoType]int{common.I: 1, common.T: 2},
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	var raw struct {
+		PieceCounts map[string]int `json:"pieceCounts"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("unmarshal raw error: %v", err)
+	}
+	if want := map[string]int{"I": 1, "T": 2}; !maps.Equal(raw.PieceCounts, want) {
+		t.Errorf("piece counts json: %v, expected %v", raw.PieceCounts, want)
+	}
+
+	var got tetris.Stats
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if !maps.Equal(got.PieceCounts, stats.PieceCounts) {
+		t.Errorf("piece counts: %v, expected %v", got.PieceCounts, stats.PieceCounts)
+	}
+}
@@ -0,0 +1,191 @@
+package tetris
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SubscribePolicy 订阅的背压策略
+//
+// 决定订阅者处理不及时时如何处理新产生的数据
+type SubscribePolicy byte
+
+// SubscribePolicy 的枚举值
+const (
+	// PolicyLatest 仅保留最新的数据，订阅者处理不及时时未接收的旧数据被新数据替换
+	PolicyLatest SubscribePolicy = iota
+	// PolicyBuffered 缓冲指定数量的数据，缓冲满时丢弃新数据
+	PolicyBuffered
+	// PolicyBlocking 缓冲满时阻塞游戏直到订阅者接收数据或取消订阅
+	//
+	// 订阅者不应在接收数据的协程中同步调用 Tetris 的方法，否则可能死锁
+	PolicyBlocking
+)
+
+// String 返回字符串表示
+func (p SubscribePolicy) String() string {
+	switch p {
+	case PolicyLatest:
+		return "Latest"
+	case PolicyBuffered:
+		return "Buffered"
+	case PolicyBlocking:
+		return "Blocking"
+	}
+	return fmt.Sprintf("Invalid(%d)", p)
+}
+
+// SubscribeOptions 订阅选项
+type SubscribeOptions struct {
+	// 背压策略
+	Policy SubscribePolicy
+	// 缓冲大小
+	//
+	// 仅对 PolicyBuffered 和 PolicyBlocking 有效， PolicyBuffered 时至少为 1
+	BufferSize int
+}
+
+// Subscription 订阅
+type Subscription[T any] struct {
+	b      *broadcaster[T]
+	policy SubscribePolicy
+	ch     chan T
+	closed bool
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// C 返回接收数据的通道
+//
+// 取消订阅或游戏结束后该通道会被关闭
+func (s *Subscription[T]) C() <-chan T {
+	return s.ch
+}
+
+// Unsubscribe 取消订阅
+//
+// 可多次调用，可在任意协程调用
+func (s *Subscription[T]) Unsubscribe() {
+	// 先通知正在阻塞发送的数据放弃发送，再移除订阅
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+	s.b.remove(s)
+}
+
+// broadcaster 向多个订阅者广播数据
+type broadcaster[T any] struct {
+	lock   sync.Mutex
+	subs   map[*Subscription[T]]struct{}
+	closed bool
+}
+
+// newBroadcaster 创建 broadcaster
+func newBroadcaster[T any]() *broadcaster[T] {
+	return &broadcaster[T]{
+		subs: make(map[*Subscription[T]]struct{}),
+	}
+}
+
+// subscribe 订阅
+func (b *broadcaster[T]) subscribe(opts SubscribeOptions) *Subscription[T] {
+	size := opts.BufferSize
+	switch opts.Policy {
+	case PolicyLatest:
+		size = 1
+	case PolicyBuffered:
+		if size < 1 {
+			size = 1
+		}
+	default:
+		if size < 0 {
+			size = 0
+		}
+	}
+
+	s := &Subscription[T]{
+		b:      b,
+		policy: opts.Policy,
+		ch:     make(chan T, size),
+		done:   make(chan struct{}),
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.closed {
+		s.closed = true
+		close(s.ch)
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// publish 向所有订阅者发送数据
+//
+// 若有订阅者因缓冲满丢弃了数据则返回 false
+func (b *broadcaster[T]) publish(v T) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	ok := true
+	for s := range b.subs {
+		switch s.policy {
+		case PolicyLatest:
+			select {
+			case s.ch <- v:
+			default:
+				// 替换未接收的旧数据
+				select {
+				case <-s.ch:
+				default:
+				}
+				select {
+				case s.ch <- v:
+				default:
+				}
+			}
+		case PolicyBlocking:
+			select {
+			case s.ch <- v:
+			case <-s.done:
+			}
+		default:
+			select {
+			case s.ch <- v:
+			default:
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// remove 移除订阅并关闭其通道
+func (b *broadcaster[T]) remove(s *Subscription[T]) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.subs, s)
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// close 关闭所有订阅
+func (b *broadcaster[T]) close() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for s := range b.subs {
+		delete(b.subs, s)
+		if !s.closed {
+			s.closed = true
+			close(s.ch)
+		}
+	}
+}
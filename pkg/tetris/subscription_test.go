@@ -0,0 +1,98 @@
+package tetris_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+)
+
+// receiveAll 接收通道中的所有数据直到通道关闭
+func receiveAll[T any](ch <-chan T) []T {
+	var ret []T
+	for v := range ch {
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// TestSubscribeEventsPolicy 测试不同背压策略的订阅在处理不及时时收到的事件
+func TestSubscribeEventsPolicy(t *testing.T) {
+	// 两次硬下落依次产生 Score 、 LockDown 、 Score 、 LockDown ，停止时产生 GameOver
+	all := []tetris.EventType{
+		tetris.EventScore, tetris.EventLockDown, tetris.EventScore, tetris.EventLockDown, tetris.EventGameOver,
+	}
+	cases := []struct {
+		name string
+		opts tetris.SubscribeOptions
+		want []tetris.EventType
+	}{
+		{name: "latest", opts: tetris.SubscribeOptions{Policy: tetris.PolicyLatest}, want: all[4:]},
+		{name: "buffered", opts: tetris.SubscribeOptions{Policy: tetris.PolicyBuffered, BufferSize: 3}, want: all[:3]},
+		{name: "buffered-min-size", opts: tetris.SubscribeOptions{Policy: tetris.PolicyBuffered}, want: all[:1]},
+		{name: "blocking", opts: tetris.SubscribeOptions{Policy: tetris.PolicyBlocking}, want: all},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			game := newManualGame(t, opts)
+			sub := game.SubscribeEvents(c.opts)
+			var received chan []tetris.Event
+			if c.opts.Policy == tetris.PolicyBlocking {
+				// 阻塞策略需要同时接收，否则游戏会被阻塞
+				received = make(chan []tetris.Event, 1)
+				go func() { received <- receiveAll(sub.C()) }()
+			}
+
+			game.Input(tetris.OpHardDrop)
+			game.Input(tetris.OpHardDrop)
+			if err := game.Stop(); err != nil {
+				t.Fatalf("stop game error: %v", err)
+			}
+
+			var events []tetris.Event
+			if received != nil {
+				events = <-received
+			} else {
+				events = receiveAll(sub.C())
+			}
+			var got []tetris.EventType
+			for _, e := range events {
+				got = append(got, e.Type)
+			}
+			if !slices.Equal(got, c.want) {
+				t.Errorf("events: %v, expected %v", got, c.want)
+			}
+		})
+	}
+}
+
+// TestUnsubscribe 测试取消订阅后通道关闭且不影响其它订阅
+func TestUnsubscribe(t *testing.T) {
+	opts := tetris.DefaultOptions
+	opts.Seed = 1
+	game := newManualGame(t, opts)
+	a := game.SubscribeFrames(tetris.SubscribeOptions{Policy: tetris.PolicyBuffered, BufferSize: 16})
+	b := game.SubscribeFrames(tetris.SubscribeOptions{Policy: tetris.PolicyBlocking})
+
+	// 阻塞的订阅取消后不再阻塞游戏
+	b.Unsubscribe()
+	b.Unsubscribe()
+	if _, ok := <-b.C(); ok {
+		t.Errorf("unsubscribed channel not closed")
+	}
+	game.Input(tetris.OpMoveLeft)
+	game.Input(tetris.OpMoveRight)
+	if err := game.Stop(); err != nil {
+		t.Fatalf("stop game error: %v", err)
+	}
+	if got := len(receiveAll(a.C())); got != 2 {
+		t.Errorf("received %d frames, expected 2", got)
+	}
+
+	// 游戏结束后订阅得到已关闭的通道
+	if _, ok := <-game.SubscribeEvents(tetris.SubscribeOptions{}).C(); ok {
+		t.Errorf("subscription after game over not closed")
+	}
+}
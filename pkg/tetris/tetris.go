@@ -3,6 +3,7 @@ package tetris
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/yhlooo/go-tetris/pkg/tetris/common"
 )
@@ -23,6 +24,14 @@ type Tetris interface {
 	Pause() error
 	// Resume 继续游戏
 	Resume() error
+	// Step 同步推进游戏指定的处理周期数（ ticket ）
+	//
+	// 仅在游戏运行时生效。通常与 ManualClock 配合使用，以获得可复现的游戏过程
+	Step(tickets int) error
+	// Snapshot 创建游戏存档
+	//
+	// 可通过 RestoreTetris 从存档恢复游戏
+	Snapshot() (Snapshot, error)
 	// SetDebug 设置调试模式
 	SetDebug(enabled bool)
 	// Debug 返回是否调试模式
@@ -34,6 +43,11 @@ type Tetris interface {
 
 	// Input 输入操作指令
 	Input(op Op)
+	// AddGarbage 从底部加入垃圾行
+	//
+	// 加入 lines 行除 holeColumn 列外均填满的垃圾行，场上方块整体上移。若有方块被顶出场外或活跃方块无法放置则游戏失败。
+	// lines 小于 0 或 holeColumn 不在 [0, 列数) 范围内时返回错误
+	AddGarbage(lines, holeColumn int) error
 
 	// Frames 获取帧通道
 	//
@@ -42,8 +56,25 @@ type Tetris interface {
 	//
 	// 通道满时产生的帧会被丢弃。
 	//
-	// 每个 Tetris 对象只有一个通道，多次调用该方法返回的是同一通道
+	// 每个 Tetris 对象只有一个通道，多次调用该方法返回的是同一通道。需要多个接收方或其它背压策略时使用 SubscribeFrames
 	Frames() <-chan Frame
+	// SubscribeFrames 订阅帧
+	//
+	// 每个订阅有独立的通道和背压策略，游戏结束或取消订阅后通道会被关闭
+	SubscribeFrames(opts SubscribeOptions) *Subscription[Frame]
+	// Events 获取事件通道
+	//
+	// 游戏运行时发生的得分、锁定、消行、 T-Spin 、暂存、升级、游戏结束等事件将通过该通道发送。
+	// 游戏结束后该通道会被关闭。
+	//
+	// 通道满时产生的事件会被丢弃。
+	//
+	// 每个 Tetris 对象只有一个通道，多次调用该方法返回的是同一通道。需要多个接收方或其它背压策略时使用 SubscribeEvents
+	Events() <-chan Event
+	// SubscribeEvents 订阅事件
+	//
+	// 每个订阅有独立的通道和背压策略，游戏结束或取消订阅后通道会被关闭
+	SubscribeEvents(opts SubscribeOptions) *Subscription[Event]
 	// CurrentFrame 获取当前帧
 	CurrentFrame() Frame
 }
@@ -92,6 +123,26 @@ const (
 	OpHardDrop
 	// OpHold 暂存当前方块
 	OpHold
+	// OpMoveRightPress 按下向右移动键
+	//
+	// 立即向右移动一格，按住超过 Options.DAS 后按 Options.ARR 自动重复移动，直到 OpMoveRightRelease
+	OpMoveRightPress
+	// OpMoveRightRelease 松开向右移动键
+	OpMoveRightRelease
+	// OpMoveLeftPress 按下向左移动键
+	//
+	// 立即向左移动一格，按住超过 Options.DAS 后按 Options.ARR 自动重复移动，直到 OpMoveLeftRelease
+	OpMoveLeftPress
+	// OpMoveLeftRelease 松开向左移动键
+	OpMoveLeftRelease
+	// OpSoftDropPress 按下软下落键
+	//
+	// 立即下落一格，按住期间以 Options.SoftDropFactor 倍速下落，直到 OpSoftDropRelease
+	OpSoftDropPress
+	// OpSoftDropRelease 松开软下落键
+	OpSoftDropRelease
+	// OpRotate180 旋转 180 度
+	OpRotate180
 )
 
 // String 返回字符串表示
@@ -111,6 +162,20 @@ func (op Op) String() string {
 		return "HardDrop"
 	case OpHold:
 		return "Hold"
+	case OpMoveRightPress:
+		return "MoveRightPress"
+	case OpMoveRightRelease:
+		return "MoveRightRelease"
+	case OpMoveLeftPress:
+		return "MoveLeftPress"
+	case OpMoveLeftRelease:
+		return "MoveLeftRelease"
+	case OpSoftDropPress:
+		return "SoftDropPress"
+	case OpSoftDropRelease:
+		return "SoftDropRelease"
+	case OpRotate180:
+		return "Rotate180"
 	}
 	return fmt.Sprintf("Invalid(%d)", op)
 }
@@ -119,8 +184,12 @@ func (op Op) String() string {
 //
 // 包含某时刻游戏画面应显示的信息，如方块位置、得分等
 type Frame struct {
-	// 场上方块填充情况
+	// 场上方块填充情况，为游戏中场的副本（ copy-on-write ，与游戏共享数据直到任一方修改）
+	//
+	// 包含可见行之上的隐藏行，第 VisibleRows 行及以上为隐藏行
 	Field common.FieldReader
+	// 可见行数
+	VisibleRows int
 	// 暂存的方块
 	HoldingTetromino *common.TetrominoType
 	// 下几个方块
@@ -131,6 +200,27 @@ type Frame struct {
 	Score int
 	// 已消除的行数
 	ClearLines int
+	// 当前连击数，连续第 2 个消行的方块连击数为 1 ，以此类推
+	Combo int
+	// 上一次消行是否为困难消行，下一次困难消行将获得 Back-to-Back 奖励
+	BackToBack bool
+	// 统计数据
+	Stats Stats
+	// 游戏模式
+	Mode GameMode
+	// 随机种子，使用自定义随机生成器时无意义
+	Seed uint64
+	// 游戏已进行的时间（不含暂停时间）
+	Elapsed time.Duration
 	// 游戏结束
 	GameOver bool
+	// 游戏结果
+	Result GameResult
+	// 导致游戏失败的顶出类型
+	TopOut TopOutType
+
+	// 锁定延迟，使用经典锁定时为 0
+	LockDelay time.Duration
+	// 活跃方块锁定前剩余的锁定延迟，方块着地时开始减少，可用于显示锁定进度
+	LockDelayRemaining time.Duration
 }
@@ -14,40 +14,68 @@ import (
 	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
 )
 
-const framesChLen = 16
+const (
+	framesChLen = 16
+	eventsChLen = 64
+)
 
 // NewTetris 创建 Tetris 游戏实例
 func NewTetris(opts Options) Tetris {
 	opts.Complete()
+	t := newDefaultTetris(opts)
+	if t.recorder != nil {
+		t.recorder.begin(opts)
+	}
+	t.field = common.NewBitField(opts.Rows+opts.HiddenRows, opts.Columns, t.newTetromino(common.TetrominoNone))
+	t.dropSpawned()
+	t.fullyResetLockDown()
+	for i := 0; i < opts.ShowNextTetrominoes+1; i++ {
+		t.nextTetrominoes = append(t.nextTetrominoes, t.randomizer.Next())
+	}
+	return t
+}
+
+// newDefaultTetris 根据已补全的选项创建 defaultTetris ，不包含场和方块
+func newDefaultTetris(opts Options) *defaultTetris {
 	t := &defaultTetris{
+		seed:  opts.Seed,
+		opts:  newSavedOptions(opts),
 		rows:  opts.Rows,
 		cols:  opts.Columns,
 		level: opts.InitialLevel,
 
-		holdEnabled: opts.HoldEnabled,
+		hiddenRows:     opts.HiddenRows,
+		partialLockOut: opts.PartialLockOut,
+
+		holdEnabled:          opts.HoldEnabled,
+		finesse:              opts.FinesseEnabled,
+		finesseMinimalInputs: map[finesseKey]int{},
+		mode:                 opts.Mode,
 
 		linesPerLevel: opts.LinesPerLevel,
-		ticker:        time.NewTicker(time.Second / time.Duration(opts.Frequency)),
 		speed:         opts.SpeedController,
 		freq:          opts.Frequency,
+		clock:         opts.Clock,
+
+		das:            opts.DAS,
+		arr:            opts.ARR,
+		softDropFactor: opts.SoftDropFactor,
 
 		lockDelay:              opts.LockDelay,
-		lockDownReset:          opts.LockDownReset,
+		lockDownPolicy:         opts.LockDownPolicy,
 		lockDelayMaxResetTimes: opts.LockDelayMaxResetTimes,
 
 		randomizer:     opts.Randomizer,
 		scorer:         opts.Scorer,
 		rotationSystem: opts.RotationSystem,
+		recorder:       opts.Recorder,
 
-		state:    StatePending,
-		framesCh: make(chan Frame, framesChLen),
+		state:  StatePending,
+		frames: newBroadcaster[Frame](),
+		events: newBroadcaster[Event](),
 
 		logger: opts.Logger,
 	}
-	t.field = common.NewField(opts.Rows, opts.Columns, t.newTetromino(common.TetrominoNone))
-	for i := 0; i < opts.ShowNextTetrominoes+1; i++ {
-		t.nextTetrominoes = append(t.nextTetrominoes, t.randomizer.Next())
-	}
 	return t
 }
 
@@ -57,39 +85,77 @@ type defaultTetris struct {
 	startOnce sync.Once
 	cancel    context.CancelFunc
 
+	seed             uint64
+	opts             SavedOptions
 	rows, cols       int
-	field            *common.Field
+	hiddenRows       int
+	partialLockOut   bool
+	field            *common.BitField
 	nextTetrominoes  []common.TetrominoType
 	holdingTetromino *common.TetrominoType
 	level            int
 	score            int
 	clearLines       int
+	backToBack       bool
+	clearStreak      int
+	stats            Stats
+
+	// 是否分析 Finesse 错误
+	finesse bool
+	// 空场中各位置的最少操作数缓存
+	finesseMinimalInputs map[finesseKey]int
+	// 当前方块使用的操作数，用于分析 Finesse 错误
+	pieceInputs       int
+	pieceSoftDropping bool
 
 	holed              bool
 	notMove            bool
+	tstKick            bool
+	tickets            int64
 	fallDownTickets    int64
 	lockDownTickets    int64
 	lockDownResetTimes int
+	// 当前方块到达过的最低行
+	lowestRow int
+
+	leftHeld, rightHeld bool
+	shiftDir            int
+	dasTickets          int64
+	arrTickets          int64
+	softDropHeld        bool
 
 	holdEnabled bool
+	mode        GameMode
+	result      GameResult
+	topOut      TopOutType
 
 	linesPerLevel int
-	ticker        *time.Ticker
 	speed         SpeedController
 	freq          int
+	clock         Clock
+
+	das            time.Duration
+	arr            time.Duration
+	softDropFactor float64
 
 	lockDelay              time.Duration
-	lockDownReset          bool
+	lockDownPolicy         LockDownPolicy
 	lockDelayMaxResetTimes int
 
 	randomizer     randomizer.Randomizer
 	scorer         Scorer
 	rotationSystem rotationsystems.RotationSystem
-
-	debug    bool
-	state    GameState
-	framesCh chan Frame
-	logger   logr.Logger
+	recorder       *Recorder
+
+	debug      bool
+	state      GameState
+	frames     *broadcaster[Frame]
+	framesSub  *Subscription[Frame]
+	framesOnce sync.Once
+	events     *broadcaster[Event]
+	eventsSub  *Subscription[Event]
+	eventsOnce sync.Once
+	logger     logr.Logger
 }
 
 var _ Tetris = (*defaultTetris)(nil)
@@ -106,9 +172,17 @@ func (t *defaultTetris) Start(ctx context.Context) error {
 		t.lock.Lock()
 		defer t.lock.Unlock()
 
-		ctx, t.cancel = context.WithCancel(ctx)
 		t.state = StateRunning
-		go t.run(ctx)
+		if ticker := t.clock.NewTicker(time.Second / time.Duration(t.freq)); ticker != nil {
+			ctx, t.cancel = context.WithCancel(ctx)
+			go t.run(ctx, ticker)
+		} else {
+			// 手动推进，没有后台协程，停止时直接关闭订阅
+			t.cancel = func() {
+				t.frames.close()
+				t.events.close()
+			}
+		}
 		t.sendFrame()
 		t.logger.Info("started")
 	})
@@ -119,10 +193,10 @@ func (t *defaultTetris) Start(ctx context.Context) error {
 func (t *defaultTetris) Stop() error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
+	t.finish()
 	if t.cancel != nil {
 		t.cancel()
 	}
-	t.state = StateFinished
 	t.logger.Info("stoped")
 	return nil
 }
@@ -135,6 +209,7 @@ func (t *defaultTetris) Pause() error {
 		return fmt.Errorf("not in running state: %s", t.state)
 	}
 	t.state = StatePaused
+	t.releaseAll()
 	t.logger.Info("paused")
 	return nil
 }
@@ -147,10 +222,24 @@ func (t *defaultTetris) Resume() error {
 		return fmt.Errorf("not in paused state: %s", t.state)
 	}
 	t.state = StateRunning
+	t.releaseAll()
 	t.logger.Info("resumed")
 	return nil
 }
 
+// Step 同步推进游戏指定的处理周期数（ ticket ）
+func (t *defaultTetris) Step(tickets int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.state != StateRunning {
+		return fmt.Errorf("not in running state: %s", t.state)
+	}
+	for i := 0; i < tickets && t.state == StateRunning; i++ {
+		t.tick()
+	}
+	return nil
+}
+
 // SetDebug 设置调试模式
 func (t *defaultTetris) SetDebug(enabled bool) {
 	t.lock.Lock()
@@ -203,46 +292,77 @@ func (t *defaultTetris) Input(op Op) {
 		t.logger.V(1).Info(fmt.Sprintf("ignore input %q: not running: %s", op, t.state))
 		return
 	}
+	if t.recorder != nil {
+		t.recorder.record(t.tickets, op)
+	}
+	switch op {
+	case OpMoveRightRelease, OpMoveLeftRelease, OpSoftDropRelease:
+	default:
+		t.stats.Inputs++
+	}
+	t.countPieceInput(op)
 
 	changed := false
 	switch op {
 	case OpMoveRight:
-		changed = t.field.MoveActiveTetromino(0, 1)
-		if changed {
-			t.notMove = false
-			t.resetLockDownDelay()
-		}
+		changed = t.shift(1)
 		t.logger.V(1).Info(fmt.Sprintf("move right, ret: %t", changed))
 	case OpMoveLeft:
-		changed = t.field.MoveActiveTetromino(0, -1)
-		if changed {
-			t.notMove = false
-			t.resetLockDownDelay()
-		}
+		changed = t.shift(-1)
 		t.logger.V(1).Info(fmt.Sprintf("move left, ret: %t", changed))
-	case OpRotateRight:
-		changed = t.rotationSystem.RotateRight(t.field)
-		if changed {
-			t.notMove = true
-			t.resetLockDownDelay()
+	case OpMoveRightPress, OpMoveLeftPress:
+		dir := 1
+		if op == OpMoveLeftPress {
+			dir = -1
+			t.leftHeld = true
+		} else {
+			t.rightHeld = true
+		}
+		// 后按下的方向优先
+		t.shiftDir = dir
+		t.dasTickets = 0
+		t.arrTickets = 0
+		changed = t.shift(dir)
+		t.logger.V(1).Info(fmt.Sprintf("%s, ret: %t", op, changed))
+	case OpMoveRightRelease, OpMoveLeftRelease:
+		if op == OpMoveLeftRelease {
+			t.leftHeld = false
+		} else {
+			t.rightHeld = false
+		}
+		// 另一方向仍按住时转向另一方向，重新计算延迟
+		t.shiftDir = 0
+		if t.leftHeld {
+			t.shiftDir = -1
+		} else if t.rightHeld {
+			t.shiftDir = 1
 		}
+		t.dasTickets = 0
+		t.arrTickets = 0
+		t.logger.V(1).Info(op.String())
+	case OpRotateRight:
+		changed = t.rotate(t.rotationSystem.RotateRight, true)
 		t.logger.V(1).Info(fmt.Sprintf("rotate right, ret: %t", changed))
 	case OpRotateLeft:
-		changed = t.rotationSystem.RotateLeft(t.field)
-		if changed {
-			t.notMove = true
-			t.resetLockDownDelay()
-		}
+		changed = t.rotate(t.rotationSystem.RotateLeft, true)
 		t.logger.V(1).Info(fmt.Sprintf("rotate left, ret: %t", changed))
+	case OpRotate180:
+		changed = t.rotate(t.rotationSystem.Rotate180, false)
+		t.logger.V(1).Info(fmt.Sprintf("rotate 180, ret: %t", changed))
 	case OpSoftDrop:
 		t.logger.V(1).Info("soft drop")
-		if ok := t.field.MoveActiveTetromino(-1, 0); ok {
-			t.notMove = false
-			t.calcScore(ScoreEvent{SoftDrop: 1})
-			t.fullyResetLockDown()
-		}
+		t.softDrop()
 		t.fallDownTickets = 0
 		changed = true
+	case OpSoftDropPress:
+		t.logger.V(1).Info("soft drop press")
+		t.softDropHeld = true
+		t.softDrop()
+		t.fallDownTickets = 0
+		changed = true
+	case OpSoftDropRelease:
+		t.logger.V(1).Info("soft drop release")
+		t.softDropHeld = false
 	case OpHardDrop:
 		t.logger.V(1).Info("hard drop")
 		dropLines := 0
@@ -268,9 +388,13 @@ func (t *defaultTetris) Input(op Op) {
 				}
 			}
 			if ok {
+				t.dropSpawned()
+				t.fullyResetLockDown()
 				t.holdingTetromino = &oldActive
 				t.holed = true
 				t.notMove = false
+				t.resetPieceInputs()
+				t.sendEvent(Event{Type: EventHold, Tetromino: common.Tetromino{Type: oldActive}, Level: t.level})
 			}
 			changed = ok
 			t.logger.V(1).Info(fmt.Sprintf("hold tetromino, ret: %t", ok))
@@ -285,151 +409,436 @@ func (t *defaultTetris) Input(op Op) {
 	}
 }
 
+// AddGarbage 从底部加入垃圾行
+func (t *defaultTetris) AddGarbage(lines, holeColumn int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.state != StateRunning && t.state != StatePaused {
+		return fmt.Errorf("not in running or paused state: %s", t.state)
+	}
+	if lines < 0 {
+		return fmt.Errorf("invalid garbage lines: %d", lines)
+	}
+	if holeColumn < 0 || holeColumn >= t.cols {
+		return fmt.Errorf("invalid garbage hole column: %d (expected 0 to %d)", holeColumn, t.cols-1)
+	}
+	if t.recorder != nil {
+		t.recorder.recordGarbage(t.tickets, lines, holeColumn)
+	}
+
+	ok := t.field.AddGarbage(lines, holeColumn)
+	t.logger.V(1).Info(fmt.Sprintf("add %d garbage lines, hole column: %d, ret: %t", lines, holeColumn, ok))
+	if !ok {
+		t.result = ResultLost
+		t.topOut = TopOutGarbage
+		t.finish()
+	}
+	t.sendFrame()
+	return nil
+}
+
 // Frames 获取帧通道
 func (t *defaultTetris) Frames() <-chan Frame {
-	return t.framesCh
+	// 首次调用时才订阅，避免无人接收时频繁丢弃帧
+	t.framesOnce.Do(func() {
+		t.framesSub = t.frames.subscribe(SubscribeOptions{Policy: PolicyBuffered, BufferSize: framesChLen})
+	})
+	return t.framesSub.C()
+}
+
+// SubscribeFrames 订阅帧
+func (t *defaultTetris) SubscribeFrames(opts SubscribeOptions) *Subscription[Frame] {
+	return t.frames.subscribe(opts)
+}
+
+// Events 获取事件通道
+func (t *defaultTetris) Events() <-chan Event {
+	t.eventsOnce.Do(func() {
+		t.eventsSub = t.events.subscribe(SubscribeOptions{Policy: PolicyBuffered, BufferSize: eventsChLen})
+	})
+	return t.eventsSub.C()
+}
+
+// SubscribeEvents 订阅事件
+func (t *defaultTetris) SubscribeEvents(opts SubscribeOptions) *Subscription[Event] {
+	return t.events.subscribe(opts)
 }
 
 // CurrentFrame 获取当前帧
 func (t *defaultTetris) CurrentFrame() Frame {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.currentFrame()
+}
+
+// currentFrame 获取当前帧
+//
+// 帧中的场和方块列表为副本，可在其它协程中读取
+func (t *defaultTetris) currentFrame() Frame {
 	return Frame{
-		Field:            t.field,
+		Field:            t.field.Clone(),
+		VisibleRows:      t.rows,
 		HoldingTetromino: t.holdingTetromino,
-		NextTetrominoes:  t.nextTetrominoes[:len(t.nextTetrominoes)-1],
+		NextTetrominoes:  append([]common.TetrominoType(nil), t.nextTetrominoes[:len(t.nextTetrominoes)-1]...),
 		Level:            t.level,
 		Score:            t.score,
 		ClearLines:       t.clearLines,
+		Combo:            t.combo(),
+		BackToBack:       t.backToBack,
+		Stats:            t.currentStats(),
+		Mode:             t.mode,
+		Seed:             t.seed,
+		Elapsed:          t.elapsed(),
 		GameOver:         t.state == StateFinished,
+		Result:           t.result,
+		TopOut:           t.topOut,
+
+		LockDelay:          t.currentLockDelay(),
+		LockDelayRemaining: t.lockDelayRemaining(),
 	}
 }
 
 // run 运行
-func (t *defaultTetris) run(ctx context.Context) {
-	defer close(t.framesCh)
-	defer t.ticker.Stop()
+func (t *defaultTetris) run(ctx context.Context, ticker Ticker) {
+	defer t.frames.close()
+	defer t.events.close()
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-t.ticker.C:
+		case <-ticker.C():
 		}
 
-		if t.state != StateRunning {
-			continue
+		t.lock.Lock()
+		if t.state == StateRunning {
+			t.tick()
 		}
+		t.lock.Unlock()
+	}
+}
 
-		t.lock.Lock()
-		t.fallDownTickets++
-		t.lockDownTickets++
+// tick 处理一个周期
+func (t *defaultTetris) tick() {
+	t.tickets++
+	t.fallDownTickets++
 
-		changed := false
+	changed := false
 
-		// 自然下落
-		speed := t.speed(t.level)
-		if t.fallDownTickets > int64(float64(t.freq)/speed) {
-			// 到时间下落一格了
-			t.logger.V(1).Info("auto drop")
-			if ok := t.field.MoveActiveTetromino(-1, 0); ok {
-				t.notMove = false
-				t.fullyResetLockDown()
+	// 按住移动键自动重复移动
+	if t.shiftDir != 0 {
+		t.dasTickets++
+		das := t.durationTickets(t.das)
+		switch {
+		case t.dasTickets < das:
+		case t.arr <= 0:
+			// 立即移动到底
+			for t.shift(t.shiftDir) {
 				changed = true
 			}
-			t.fallDownTickets = 0
+		case t.dasTickets == das:
+			// 刚开始自动重复，立即移动一格
+			changed = t.shift(t.shiftDir) || changed
+		default:
+			t.arrTickets++
+			if t.arrTickets >= t.durationTickets(t.arr) {
+				changed = t.shift(t.shiftDir) || changed
+				t.arrTickets = 0
+			}
 		}
+	}
 
-		// 锁定
-		if t.lockDownTickets > (int64(t.lockDelay)*int64(t.freq))/int64(time.Second) {
-			if ok := t.field.MoveActiveTetromino(-1, 0); !ok {
-				// 下方没有空间了，锁定
+	// 自然下落
+	fallDownInterval := float64(t.freq) / t.speed(t.level)
+	if t.softDropHeld {
+		fallDownInterval /= t.softDropFactor
+	}
+	if t.fallDownTickets > int64(fallDownInterval) {
+		// 到时间下落一格了
+		if t.softDropHeld {
+			t.logger.V(1).Info("soft drop")
+			changed = t.softDrop() || changed
+		} else {
+			t.logger.V(1).Info("auto drop")
+			if ok := t.field.MoveActiveTetromino(-1, 0); ok {
+				t.notMove = false
+				t.resetLockDownOnDrop()
+				changed = true
+			} else if t.lockDownPolicy == LockDownClassic {
 				t.lockDown()
-				t.logger.Info(fmt.Sprintf("lock down, tickets: %d", t.lockDownTickets))
+				t.logger.Info("lock down")
 				changed = true
-			} else {
-				// 下方还有空间，还原
-				t.field.MoveActiveTetromino(1, 0)
 			}
 		}
+		t.fallDownTickets = 0
+	}
 
-		if changed {
-			t.sendFrame()
+	// 锁定，锁定延迟只在方块着地时计时
+	if t.state == StateRunning && t.lockDownPolicy != LockDownClassic && t.grounded() {
+		t.lockDownTickets++
+		if t.lockDownTickets > t.durationTickets(t.lockDelay) {
+			t.logger.Info(fmt.Sprintf("lock down, tickets: %d", t.lockDownTickets))
+			t.lockDown()
+			changed = true
 		}
+	}
 
-		t.lock.Unlock()
+	// 检查是否达成模式目标
+	if t.checkGoal() {
+		changed = true
+	}
+
+	// 定时发送帧以更新计时
+	if t.freq < 10 || t.tickets%int64(t.freq/10) == 0 {
+		changed = true
+	}
+
+	if changed {
+		t.sendFrame()
 	}
 }
 
 // lockDown 锁定当前活跃方块
 func (t *defaultTetris) lockDown() {
-	tSpin, clearLines, ok := t.field.LockDown(t.newTetromino(t.nextTetrominoes[0]))
-	t.calcScore(ScoreEvent{TSpin: tSpin && t.notMove, ClearLines: clearLines})
+	locked := *t.field.ActiveTetromino()
+	topOut := t.lockOut(locked)
+	faults := t.finesseFaults()
+	t.resetPieceInputs()
+	tSpin, clearLines, perfectClear, ok := t.field.LockDown(t.newTetromino(t.nextTetrominoes[0]))
+	switch {
+	case !t.notMove:
+		// 最后一次操作不是旋转
+		tSpin = common.TSpinNone
+	case tSpin == common.TSpinMini && t.tstKick:
+		tSpin = common.TSpinFull
+	}
+	if clearLines > 0 {
+		t.clearStreak++
+	} else {
+		t.clearStreak = 0
+	}
+	score, reason := t.calcScore(ScoreEvent{
+		TSpin:        tSpin,
+		ClearLines:   clearLines,
+		PerfectClear: perfectClear,
+		BackToBack:   t.backToBack,
+		Combo:        t.combo(),
+	})
+	t.stats.addLockDown(locked.Type, tSpin, clearLines, perfectClear, t.backToBack, t.combo())
+	t.stats.FinesseFaults += faults
+	if clearLines > 0 {
+		t.backToBack = tSpin != common.TSpinNone || clearLines >= 4
+	}
 	t.clearLines += clearLines
+	oldLevel := t.level
 	t.level = t.clearLines/t.linesPerLevel + 1
-	if !ok {
-		t.state = StateFinished
+
+	event := Event{
+		Type:          EventLockDown,
+		Tetromino:     locked,
+		ClearLines:    clearLines,
+		TSpin:         tSpin,
+		PerfectClear:  perfectClear,
+		Combo:         t.combo(),
+		Score:         score,
+		FinesseFaults: faults,
+		Reason:        reason,
+		Level:         t.level,
+	}
+	t.sendEvent(event)
+	if clearLines > 0 {
+		event.Type = EventLineClear
+		t.sendEvent(event)
+	}
+	if tSpin != common.TSpinNone {
+		event.Type = EventTSpin
+		t.sendEvent(event)
+	}
+	if perfectClear {
+		event.Type = EventPerfectClear
+		t.sendEvent(event)
+	}
+	if t.level > oldLevel {
+		t.sendEvent(Event{Type: EventLevelUp, Level: t.level})
+	}
+
+	if !t.checkGoal() {
+		if topOut == TopOutNone && !ok {
+			topOut = TopOutBlockOut
+		}
+		if topOut != TopOutNone {
+			t.result = ResultLost
+			t.topOut = topOut
+			t.finish()
+		} else {
+			t.dropSpawned()
+		}
 	}
 	t.nextTetrominoes = append(t.nextTetrominoes[1:], t.randomizer.Next())
 	t.holed = false
 	t.fullyResetLockDown()
 }
 
-// resetLockDownDelay 重置锁定延迟计数器
-func (t *defaultTetris) resetLockDownDelay() {
-	if !t.lockDownReset {
-		return
+// currentStats 返回当前统计数据
+func (t *defaultTetris) currentStats() Stats {
+	stats := t.stats.clone()
+	stats.Elapsed = t.elapsed()
+	return stats
+}
+
+// combo 返回当前连击数
+//
+// 连续第 2 个消行的方块连击数为 1 ，以此类推，未连续消行时为 0
+func (t *defaultTetris) combo() int {
+	return max(t.clearStreak-1, 0)
+}
+
+// rotate 使用指定旋转方法旋转活跃方块
+//
+// quarter 表示是否为 90 度旋转。 SRS 中 90 度旋转使用第 5 个踢墙测试成功时，之后判定的 T-Spin Mini 将升级为 T-Spin
+func (t *defaultTetris) rotate(rotate func(field common.MutableField) (rotationsystems.Kick, bool), quarter bool) bool {
+	kick, ok := rotate(t.field)
+	if ok {
+		t.notMove = true
+		t.tstKick = quarter && kick.Index == 4
+		t.resetLockDownDelay()
 	}
-	if t.lockDelayMaxResetTimes > 0 && t.lockDownResetTimes > t.lockDelayMaxResetTimes {
-		return
+	return ok
+}
+
+// shift 左右移动活跃方块， dir 为 1 向右、为 -1 向左
+func (t *defaultTetris) shift(dir int) bool {
+	ok := t.field.MoveActiveTetromino(0, dir)
+	if ok {
+		t.notMove = false
+		t.resetLockDownDelay()
+	}
+	return ok
+}
+
+// softDrop 软下落一格
+//
+// 使用经典锁定时，下落受阻则立即锁定
+func (t *defaultTetris) softDrop() bool {
+	ok := t.field.MoveActiveTetromino(-1, 0)
+	if ok {
+		t.notMove = false
+		t.calcScore(ScoreEvent{SoftDrop: 1})
+		t.resetLockDownOnDrop()
+	} else if t.lockDownPolicy == LockDownClassic {
+		t.lockDown()
+		t.logger.V(1).Info("lock down tetromino")
+		return true
+	}
+	return ok
+}
+
+// releaseAll 松开所有按住的按键
+func (t *defaultTetris) releaseAll() {
+	t.leftHeld = false
+	t.rightHeld = false
+	t.shiftDir = 0
+	t.dasTickets = 0
+	t.arrTickets = 0
+	t.softDropHeld = false
+}
+
+// elapsed 返回游戏已进行的时间
+func (t *defaultTetris) elapsed() time.Duration {
+	return time.Duration(t.tickets) * time.Second / time.Duration(t.freq)
+}
+
+// durationTickets 返回指定时长对应的周期数
+func (t *defaultTetris) durationTickets(d time.Duration) int64 {
+	return int64(d) * int64(t.freq) / int64(time.Second)
+}
+
+// checkGoal 检查是否达成游戏模式目标，达成时以胜利结束游戏并返回 true
+func (t *defaultTetris) checkGoal() bool {
+	if t.state == StateFinished || !t.mode.check(t.level, t.clearLines, t.elapsed()) {
+		return false
 	}
-	t.lockDownTickets = 0
-	t.lockDownResetTimes++
+	t.result = ResultWon
+	t.finish()
+	return true
 }
 
-// fullyResetLockDown 完全重置锁定延迟相关计数器
-func (t *defaultTetris) fullyResetLockDown() {
-	t.lockDownTickets = 0
-	t.lockDownResetTimes = 0
+// finish 结束游戏
+func (t *defaultTetris) finish() {
+	if t.state == StateFinished {
+		return
+	}
+	t.state = StateFinished
+	if t.recorder != nil {
+		t.recorder.end(t.tickets)
+	}
+	t.sendEvent(Event{Type: EventGameOver, Score: t.score, Level: t.level, Result: t.result, TopOut: t.topOut})
 }
 
-// sendFrame 发送帧
+// sendFrame 向所有订阅者发送当前帧
+//
+// 有订阅者因缓冲满丢弃了帧时返回 false
 func (t *defaultTetris) sendFrame() bool {
-	select {
-	case t.framesCh <- t.CurrentFrame():
-	default:
+	return t.frames.publish(t.currentFrame())
+}
+
+// sendEvent 向所有订阅者发送事件
+//
+// 有订阅者因缓冲满丢弃了事件时返回 false
+func (t *defaultTetris) sendEvent(event Event) bool {
+	event.Ticket = t.tickets
+	if ok := t.events.publish(event); !ok {
+		t.logger.V(1).Info(fmt.Sprintf("WARN: events channel busy, event %s dropped", event.Type))
 		return false
 	}
 	return true
 }
 
 // calcScore 计算分数
-func (t *defaultTetris) calcScore(event ScoreEvent) {
+func (t *defaultTetris) calcScore(event ScoreEvent) (int, []string) {
 	score, reason := t.scorer(t.level, event)
 	if score > 0 {
 		t.score += score
 		t.logger.Info(fmt.Sprintf("SCORE %s: +%d", strings.Join(reason, ", "), score))
+		t.sendEvent(Event{Type: EventScore, Score: score, Reason: reason, Level: t.level})
 	}
+	return score, reason
 }
 
 // newTetromino 创建新方块
+//
+// 有隐藏行时方块出生在可见行之上的两行中
 func (t *defaultTetris) newTetromino(tetrominoType common.TetrominoType) *common.Tetromino {
 	if tetrominoType == common.TetrominoNone {
 		tetrominoType = t.randomizer.Next()
 	}
+	tetromino := t.rotationSystem.Spawn(tetrominoType, spawnRows(t.rows, t.hiddenRows), t.cols)
+	return &tetromino
+}
 
-	// 确定位置，放在居中上方刚好露出完整方块的位置
-	col := t.cols/2 - 2
-	row := t.rows - 3
-	switch tetrominoType {
-	case common.O:
-		col = t.cols/2 - 1
-		row = t.rows - 2
-	default:
+// dropSpawned 有隐藏行时，新出生的方块下方没有阻挡则立即下落一格
+func (t *defaultTetris) dropSpawned() {
+	if t.hiddenRows > 0 {
+		t.field.MoveActiveTetromino(-1, 0)
 	}
+}
 
-	return &common.Tetromino{
-		Type:   tetrominoType,
-		Row:    row,
-		Column: col,
-		Dir:    common.Dir0,
+// lockOut 检查锁定的方块是否导致 Lock Out 或 Partial Lock Out
+func (t *defaultTetris) lockOut(locked common.Tetromino) TopOutType {
+	cells := locked.Cells()
+	above := 0
+	for _, cell := range cells {
+		if cell.Row() >= t.rows {
+			above++
+		}
+	}
+	switch {
+	case above == len(cells):
+		return TopOutLockOut
+	case above > 0 && t.partialLockOut:
+		return TopOutPartialLockOut
 	}
+	return TopOutNone
 }
@@ -0,0 +1,125 @@
+package tetris_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// TestSpawn 测试有无隐藏行时新方块的出生位置
+func TestSpawn(t *testing.T) {
+	cases := []struct {
+		name       string
+		hiddenRows int
+		wantRows   int
+		// 活跃方块最低格子所在行
+		wantBottom int
+	}{
+		// 在可见行之上出生后立即下落一格
+		{name: "hidden-rows", hiddenRows: 20, wantRows: 40, wantBottom: 19},
+		{name: "one-hidden-row", hiddenRows: 1, wantRows: 21, wantBottom: 18},
+		{name: "no-hidden-rows", hiddenRows: 0, wantRows: 20, wantBottom: 18},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.HiddenRows = c.hiddenRows
+			frame := newManualGame(t, opts).CurrentFrame()
+			if frame.VisibleRows != 20 {
+				t.Errorf("visible rows: %d, expected 20", frame.VisibleRows)
+			}
+			if rows, _ := frame.Field.Size(); rows != c.wantRows {
+				t.Errorf("field rows: %d, expected %d", rows, c.wantRows)
+			}
+			bottom := -1
+			for _, cell := range frame.Field.ActiveTetromino().Cells() {
+				if bottom < 0 || cell.Row() < bottom {
+					bottom = cell.Row()
+				}
+			}
+			if bottom != c.wantBottom {
+				t.Errorf("active tetromino bottom row: %d, expected %d", bottom, c.wantBottom)
+			}
+
+			// SpawnTetromino 与游戏中的出生位置一致
+			active := *frame.Field.ActiveTetromino()
+			field := common.NewBitFieldFromReader(frame.Field)
+			spawn, ok := tetris.SpawnTetromino(field, rotationsystems.SuperRotationSystem{}, active.Type, frame.VisibleRows)
+			if !ok || spawn != active {
+				t.Errorf("spawn tetromino: %+v (ok: %t), expected %+v", spawn, ok, active)
+			}
+		})
+	}
+}
+
+// TestTopOut 测试各种顶出条件
+func TestTopOut(t *testing.T) {
+	cases := []struct {
+		name           string
+		partialLockOut bool
+		// 场最底部的若干行，从上往下
+		rows       []string
+		active     common.Tetromino
+		wantTopOut tetris.TopOutType
+	}{
+		{
+			// 方块锁定在可见行之上
+			name:       "lock-out",
+			rows:       slices.Repeat([]string{"GG........"}, 20),
+			active:     common.Tetromino{Type: common.O, Row: 20, Column: 0},
+			wantTopOut: tetris.TopOutLockOut,
+		},
+		{
+			name:           "partial-lock-out",
+			partialLockOut: true,
+			rows:           slices.Repeat([]string{"GG........"}, 19),
+			active:         common.Tetromino{Type: common.O, Row: 20, Column: 0},
+			wantTopOut:     tetris.TopOutPartialLockOut,
+		},
+		{
+			// 未开启 Partial Lock Out 规则时部分位于可见行之上不会失败
+			name:       "partial-lock-out-disabled",
+			rows:       slices.Repeat([]string{"GG........"}, 19),
+			active:     common.Tetromino{Type: common.O, Row: 20, Column: 0},
+			wantTopOut: tetris.TopOutNone,
+		},
+		{
+			// 下一个方块的出生位置被阻挡
+			name:       "block-out",
+			rows:       slices.Repeat([]string{"...GGGG..."}, 21),
+			active:     common.Tetromino{Type: common.O, Row: 0, Column: 0},
+			wantTopOut: tetris.TopOutBlockOut,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			opts.PartialLockOut = c.partialLockOut
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			setSnapshotField(&snapshot, c.rows...)
+			snapshot.ActiveTetromino = c.active
+			game := restoreManualGame(t, snapshot)
+			game.Input(tetris.OpHardDrop)
+
+			frame := game.CurrentFrame()
+			if frame.TopOut != c.wantTopOut {
+				t.Errorf("top out: %s, expected %s", frame.TopOut, c.wantTopOut)
+			}
+			wantResult := tetris.ResultLost
+			if c.wantTopOut == tetris.TopOutNone {
+				wantResult = tetris.ResultNone
+			}
+			if frame.Result != wantResult {
+				t.Errorf("result: %s, expected %s", frame.Result, wantResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package tetris_test
+
+import (
+	"testing"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+)
+
+// TestTSpin 测试旋转进入的 T 方块锁定时的 T-Spin 判定
+func TestTSpin(t *testing.T) {
+	cases := []struct {
+		name      string
+		rows      []string
+		active    common.Tetromino
+		ops       []tetris.Op
+		wantTSpin common.TSpinType
+		wantLines int
+		wantScore int
+	}{
+		{
+			name: "t-spin-double",
+			rows: []string{
+				"G.........",
+				"...GGGGGGG",
+				"G.GGGGGGGG",
+			},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.DirR},
+			ops:       []tetris.Op{tetris.OpRotateRight, tetris.OpHardDrop},
+			wantTSpin: common.TSpinFull,
+			wantLines: 2,
+			wantScore: 1200,
+		},
+		{
+			name: "no-rotation",
+			rows: []string{
+				"G.........",
+				"...GGGGGGG",
+				"G.GGGGGGGG",
+			},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir2},
+			ops:       []tetris.Op{tetris.OpHardDrop},
+			wantTSpin: common.TSpinNone,
+			wantLines: 2,
+			wantScore: 300,
+		},
+		{
+			// 使用第二个踢墙位置转入，凸起一侧只有一个角被占用
+			name: "t-spin-mini-single",
+			rows: []string{
+				"..........",
+				"..........",
+				".GGGGGGGGG",
+			},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 0, Dir: common.Dir0},
+			ops:       []tetris.Op{tetris.OpRotateRight, tetris.OpHardDrop},
+			wantTSpin: common.TSpinMini,
+			wantLines: 1,
+			wantScore: 200,
+		},
+		{
+			// 使用第五个踢墙位置转入时 T-Spin Mini 升级为 T-Spin
+			name: "tst-kick",
+			rows: []string{
+				"..G.......",
+				"..........",
+				".G.G......",
+				"G..GGGGGGG",
+				"G..GGGGGGG",
+			},
+			active:    common.Tetromino{Type: common.T, Row: 2, Column: 0, Dir: common.Dir0},
+			ops:       []tetris.Op{tetris.OpRotateLeft, tetris.OpHardDrop},
+			wantTSpin: common.TSpinFull,
+			wantLines: 1,
+			wantScore: 800,
+		},
+		{
+			name: "move-after-rotation",
+			rows: []string{
+				"..........",
+				"..........",
+				"..........",
+			},
+			active:    common.Tetromino{Type: common.T, Row: 0, Column: 3, Dir: common.Dir0},
+			ops:       []tetris.Op{tetris.OpRotateRight, tetris.OpMoveLeft, tetris.OpHardDrop},
+			wantTSpin: common.TSpinNone,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts := tetris.DefaultOptions
+			opts.Seed = 1
+			snapshot, err := newManualGame(t, opts).Snapshot()
+			if err != nil {
+				t.Fatalf("snapshot error: %v", err)
+			}
+			setSnapshotField(&snapshot, c.rows...)
+			snapshot.ActiveTetromino = c.active
+			game := restoreManualGame(t, snapshot)
+			events := game.Events()
+			for _, op := range c.ops {
+				game.Input(op)
+			}
+
+			var lockDown *tetris.Event
+			for lockDown == nil {
+				select {
+				case e := <-events:
+					if e.Type == tetris.EventLockDown {
+						lockDown = &e
+					}
+				default:
+					t.Fatalf("no lock down event")
+				}
+			}
+			if lockDown.TSpin != c.wantTSpin {
+				t.Errorf("t-spin: %s, expected %s", lockDown.TSpin, c.wantTSpin)
+			}
+			if lockDown.ClearLines != c.wantLines {
+				t.Errorf("clear lines: %d, expected %d", lockDown.ClearLines, c.wantLines)
+			}
+			if c.wantScore > 0 && lockDown.Score != c.wantScore {
+				t.Errorf("score: %d (%v), expected %d", lockDown.Score, lockDown.Reason, c.wantScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package tty
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+)
+
+const (
+	// keyRepeatWindow 两次按键事件间隔小于该值时视为系统按键重复，需大于系统按键重复的初始延迟
+	keyRepeatWindow = 700 * time.Millisecond
+	// keyReleaseTimeout 按住的按键超过该时长没有重复事件时视为已松开
+	//
+	// 需大于系统按键重复的间隔，且小于游戏的 DAS ，以免连续轻按被当作按住而自动重复移动
+	keyReleaseTimeout = 150 * time.Millisecond
+)
+
+// NewKeyController 创建 KeyController
+func NewKeyController() *KeyController {
+	return &KeyController{
+		keys: map[tetris.Op]*heldKey{
+			tetris.OpMoveLeft:  {press: tetris.OpMoveLeftPress, release: tetris.OpMoveLeftRelease},
+			tetris.OpMoveRight: {press: tetris.OpMoveRightPress, release: tetris.OpMoveRightRelease},
+			tetris.OpSoftDrop:  {press: tetris.OpSoftDropPress, release: tetris.OpSoftDropRelease},
+		},
+	}
+}
+
+// KeyController 按键控制器
+//
+// 终端只提供按下按键和系统按键重复的事件，没有松开按键的事件，因此通过按键重复模拟按住和松开：
+// 首次按下时输入单次操作（如 OpMoveLeft ）；在 keyRepeatWindow 内再次收到同一按键时视为按住，输入按下操作（如 OpMoveLeftPress ），
+// 之后由游戏按 DAS 、 ARR 自动重复；超过 keyReleaseTimeout 没有重复事件时输入松开操作。
+// 因此在终端中开始自动重复前的延迟为系统按键重复的初始延迟加上游戏的 DAS
+type KeyController struct {
+	lock sync.Mutex
+	keys map[tetris.Op]*heldKey
+}
+
+// heldKey 可按住的按键状态
+type heldKey struct {
+	press, release tetris.Op
+
+	tetris    tetris.Tetris
+	lastEvent time.Time
+	held      bool
+	timer     *time.Timer
+}
+
+// Input 输入按键对应的操作
+//
+// op 为可按住的操作（ OpMoveLeft 、 OpMoveRight 、 OpSoftDrop ）时模拟按住和松开，其它操作直接输入
+func (c *KeyController) Input(t tetris.Tetris, op tetris.Op) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key, ok := c.keys[op]
+	if !ok {
+		t.Input(op)
+		return
+	}
+
+	now := time.Now()
+	repeat := key.tetris == t && now.Sub(key.lastEvent) < keyRepeatWindow
+	key.tetris = t
+	key.lastEvent = now
+	switch {
+	case key.held:
+		// 仍按住，等待下次重复事件
+	case repeat:
+		key.held = true
+		t.Input(key.press)
+	default:
+		t.Input(op)
+	}
+	if key.held {
+		if key.timer != nil {
+			key.timer.Stop()
+		}
+		key.timer = time.AfterFunc(keyReleaseTimeout, func() { c.release(key) })
+	}
+}
+
+// ReleaseAll 松开所有按键
+//
+// 暂停、结束游戏时调用，游戏暂停时会自行松开所有按键，因此不再输入松开操作
+func (c *KeyController) ReleaseAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, key := range c.keys {
+		if key.timer != nil {
+			key.timer.Stop()
+		}
+		*key = heldKey{press: key.press, release: key.release}
+	}
+}
+
+// release 超时未收到重复事件时松开按键
+func (c *KeyController) release(key *heldKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !key.held || time.Since(key.lastEvent) < keyReleaseTimeout {
+		return
+	}
+	key.held = false
+	key.tetris.Input(key.release)
+}
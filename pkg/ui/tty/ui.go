@@ -2,7 +2,13 @@ package tty
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/bombsimon/logrusr/v4"
 	"github.com/gdamore/tcell/v2"
@@ -10,26 +16,50 @@ import (
 	"github.com/rivo/tview"
 	"github.com/sirupsen/logrus"
 
+	"github.com/yhlooo/go-tetris/pkg/bot"
 	"github.com/yhlooo/go-tetris/pkg/tetris"
 	"github.com/yhlooo/go-tetris/pkg/tetris/common"
 )
 
+// announceDuration 提示信息显示时长
+const announceDuration = 2 * time.Second
+
+// Options 界面选项
+type Options struct {
+	// 随机生成器名，可选值见 randomizer.Names ，为空时使用 7-Bag
+	Randomizer string
+	// 旋转系统名，可选值见 rotationsystems.Names ，为空时使用 SRS
+	RotationSystem string
+	// 方块集合名，需为内置集合名或已通过 pieces.Load 加载的集合名，为空时使用七种标准方块
+	PieceSet string
+	// 随机种子，每局游戏均使用该种子， 0 表示每局使用随机的种子
+	Seed uint64
+}
+
 // NewGameUI 创建 GameUI
-func NewGameUI() *GameUI {
-	return &GameUI{}
+func NewGameUI(opts Options) *GameUI {
+	return &GameUI{
+		opts: opts,
+		keys: NewKeyController(),
+	}
 }
 
 // GameUI 基于终端的游戏用户交互界面
 type GameUI struct {
+	opts Options
+
 	app                                             *tview.Application
 	pages                                           *tview.Pages
 	holdBox, scoreBox, levelBox, linesBox, stateBox *tview.TextView
+	timeBox, announceBox, comboBox, statsBox        *tview.TextView
 	fieldBox                                        *tview.TextView
 	nextBox                                         *tview.TextView
 	logBox                                          *tview.TextView
 	gameOverBox                                     *tview.TextView
 
 	tetris       tetris.Tetris
+	keys         *KeyController
+	stopBot      context.CancelFunc
 	logrusLogger *logrus.Logger
 	logger       logr.Logger
 }
@@ -50,6 +80,7 @@ func (ui *GameUI) newRoot() tview.Primitive {
 		AddPage("main", ui.newMainPage(), true, true).
 		AddPage("pause", ui.newPauseMenuPage(), true, false).
 		AddPage("menu", ui.newMainMenuPage(), true, true).
+		AddPage("modes", ui.newModeMenuPage(), true, false).
 		AddPage("over", ui.newGameOverPage(), true, false)
 
 	return tview.NewFlex().
@@ -72,6 +103,12 @@ func (ui *GameUI) newMainPage() tview.Primitive {
 	ui.linesBox = tview.NewTextView()
 	ui.linesBox.SetTextAlign(tview.AlignCenter).SetBorder(true).SetTitle("Lines")
 
+	ui.timeBox = tview.NewTextView()
+	ui.timeBox.SetTextAlign(tview.AlignCenter).SetBorder(true).SetTitle("Time")
+
+	ui.announceBox = tview.NewTextView()
+	ui.announceBox.SetTextAlign(tview.AlignCenter).SetDynamicColors(true)
+
 	ui.stateBox = tview.NewTextView()
 	ui.stateBox.SetTextAlign(tview.AlignCenter).SetDynamicColors(true)
 
@@ -81,6 +118,12 @@ func (ui *GameUI) newMainPage() tview.Primitive {
 	ui.nextBox = tview.NewTextView()
 	ui.nextBox.SetDynamicColors(true).SetBorder(true).SetTitle("Next")
 
+	ui.comboBox = tview.NewTextView()
+	ui.comboBox.SetTextAlign(tview.AlignCenter).SetDynamicColors(true)
+
+	ui.statsBox = tview.NewTextView()
+	ui.statsBox.SetBorder(true).SetTitle("Stats")
+
 	ui.logBox = tview.NewTextView().SetScrollable(true).SetDynamicColors(true)
 	ui.logrusLogger = logrus.New()
 	ui.logrusLogger.Out = ui.logBox
@@ -92,13 +135,16 @@ func (ui *GameUI) newMainPage() tview.Primitive {
 
 	leftFlex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(ui.holdBox, 6, 1, false).
-		AddItem(tview.NewBox(), 3, 1, false).
+		AddItem(ui.timeBox, 3, 1, false).
 		AddItem(ui.scoreBox, 3, 1, false).
 		AddItem(ui.levelBox, 3, 1, false).
 		AddItem(ui.linesBox, 3, 1, false).
+		AddItem(ui.announceBox, 2, 1, false).
 		AddItem(ui.stateBox, 0, 1, false)
 	rightFlex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(ui.nextBox, 12, 1, false).
+		AddItem(ui.comboBox, 2, 1, false).
+		AddItem(ui.statsBox, 7, 1, false).
 		AddItem(tview.NewBox(), 0, 1, false)
 
 	mainPage := tview.NewFlex().SetDirection(tview.FlexRow).
@@ -118,8 +164,10 @@ func (ui *GameUI) newMainPage() tview.Primitive {
 func (ui *GameUI) newMainMenuPage() tview.Primitive {
 	mainMenu := tview.NewTable().SetSelectable(true, true).
 		SetCell(0, 0, tview.NewTableCell("   Play   ").SetAlign(tview.AlignCenter)).
-		SetCell(1, 0, tview.NewTableCell("   Help   ").SetAlign(tview.AlignCenter)).
-		SetCell(2, 0, tview.NewTableCell("  !About  ").SetAlign(tview.AlignCenter))
+		SetCell(1, 0, tview.NewTableCell(" Watch AI ").SetAlign(tview.AlignCenter)).
+		SetCell(2, 0, tview.NewTableCell(" Continue ").SetAlign(tview.AlignCenter)).
+		SetCell(3, 0, tview.NewTableCell("   Help   ").SetAlign(tview.AlignCenter)).
+		SetCell(4, 0, tview.NewTableCell("  !About  ").SetAlign(tview.AlignCenter))
 	mainMenu.SetBorder(true)
 	mainMenu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
@@ -131,27 +179,64 @@ func (ui *GameUI) newMainMenuPage() tview.Primitive {
 		row, _ := mainMenu.GetSelection()
 		switch row {
 		case 0:
-			// 开始游戏
-			ui.startGame()
+			// 选择模式
+			ui.pages.SwitchToPage("main")
+			ui.pages.ShowPage("modes")
 		case 1:
-			ui.pages.SwitchToPage("help")
+			// 观看 AI 游戏
+			ui.watchAI()
 		case 2:
+			// 继续存档的游戏
+			ui.continueGame()
+		case 3:
+			ui.pages.SwitchToPage("help")
+		case 4:
 			ui.pages.SwitchToPage("about")
 		}
 		return event
 	})
-	mainMenuPage := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(mainMenu, 5, 1, true)
+	mainMenuPage := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(mainMenu, 7, 1, true)
 	mainMenuPage.SetBorderPadding(8, 0, 17, 17)
 
 	return mainMenuPage
 }
 
+// newModeMenuPage 创建游戏模式选择菜单页
+func (ui *GameUI) newModeMenuPage() tview.Primitive {
+	menu := tview.NewTable().SetSelectable(true, true)
+	for i, mode := range tetris.BuiltinGameModes {
+		menu.SetCell(i, 0, tview.NewTableCell(padCenter(mode.Name, 10)).SetAlign(tview.AlignCenter))
+	}
+	menu.SetBorder(true).SetTitle("Mode")
+	menu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+		case tcell.KeyEsc:
+			// 回到主菜单
+			ui.pages.SwitchToPage("main")
+			ui.pages.ShowPage("menu")
+			return event
+		default:
+			return event
+		}
+		// 开始游戏
+		row, _ := menu.GetSelection()
+		ui.startGame(tetris.BuiltinGameModes[row])
+		return event
+	})
+	menuPage := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(menu, len(tetris.BuiltinGameModes)+2, 1, true)
+	menuPage.SetBorderPadding(8, 0, 17, 17)
+
+	return menuPage
+}
+
 // newPauseMenuPage 创建暂停菜单页
 func (ui *GameUI) newPauseMenuPage() tview.Primitive {
 	menu := tview.NewTable().SetSelectable(true, true).
 		SetCell(0, 0, tview.NewTableCell("  Resume  ").SetAlign(tview.AlignCenter)).
 		SetCell(1, 0, tview.NewTableCell("   Help   ").SetAlign(tview.AlignCenter)).
-		SetCell(2, 0, tview.NewTableCell("   Quit   ").SetAlign(tview.AlignCenter))
+		SetCell(2, 0, tview.NewTableCell(" Save&Quit").SetAlign(tview.AlignCenter)).
+		SetCell(3, 0, tview.NewTableCell("   Quit   ").SetAlign(tview.AlignCenter))
 	menu.SetBorder(true)
 	menu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
@@ -171,12 +256,15 @@ func (ui *GameUI) newPauseMenuPage() tview.Primitive {
 		case 1:
 			ui.pages.SwitchToPage("help")
 		case 2:
+			// 保存并结束游戏
+			ui.saveAndStopGame()
+		case 3:
 			// 结束游戏
 			ui.stopGame()
 		}
 		return event
 	})
-	menuPage := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(menu, 5, 1, true)
+	menuPage := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(menu, 6, 1, true)
 	menuPage.SetBorderPadding(8, 0, 17, 17)
 
 	return menuPage
@@ -200,7 +288,7 @@ func (ui *GameUI) newGameOverPage() tview.Primitive {
 			return event
 		})
 
-	gameOverPage := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(ui.gameOverBox, 6, 1, true)
+	gameOverPage := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(ui.gameOverBox, 9, 1, true)
 	gameOverPage.SetBorderPadding(8, 0, 0, 0)
 	return gameOverPage
 }
@@ -215,6 +303,7 @@ func (ui *GameUI) newHelpPage() tview.Primitive {
        Right / d / l : Move Right
         Down / s / k : Soft Drop
                    z : Rotate Left
+                   x : Rotate 180
                    c : Hold
                Space : Hard Drop
                  ESC : Pause
@@ -233,8 +322,14 @@ func (ui *GameUI) newHelpPage() tview.Primitive {
     T-Spin Single                    800
     T-Spin Double                   1200
     T-Spin Triple                   1600
+    T-Spin Mini                      100
+    T-Spin Mini Single               200
+    T-Spin Mini Double               400
+    Perfect Clear     800/1200/1800/2000
+    B2B Tetris Perfect Clear        3200
     Back-to-Back            0.5 * Tetris
                                or T-Spin
+    Combo                     50 * Combo
 
    [lightgray](Press ENTER or ESC to back to menu)[white]
 `)
@@ -252,7 +347,7 @@ func (ui *GameUI) newHelpPage() tview.Primitive {
 		}
 		return event
 	})
-	return tview.NewFlex().SetDirection(tview.FlexRow).AddItem(helpBox, 30, 1, true)
+	return tview.NewFlex().SetDirection(tview.FlexRow).AddItem(helpBox, 37, 1, true)
 }
 
 // newAboutPage 创建关于页
@@ -289,13 +384,73 @@ see https://github.com/yhlooo/go-tetris .
 	return tview.NewFlex().SetDirection(tview.FlexRow).AddItem(aboutBox, 22, 1, true)
 }
 
-// startGame 开始游戏
-func (ui *GameUI) startGame() {
+// startGame 以指定模式开始游戏
+func (ui *GameUI) startGame(mode tetris.GameMode) {
 	ui.logrusLogger.SetLevel(logrus.InfoLevel)
+	opts := ui.gameOptions()
+	opts.Mode = mode
+	ui.runGame(tetris.NewTetris(opts))
+}
+
+// watchAI 开始由 AI 操作的游戏
+func (ui *GameUI) watchAI() {
+	ui.logrusLogger.SetLevel(logrus.InfoLevel)
+	opts := ui.gameOptions()
+	opts.Complete()
+	t := tetris.NewTetris(opts)
+	ui.runGame(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ui.stopBot = cancel
+	botOpts := bot.DefaultOptions
+	botOpts.RotationSystem = opts.RotationSystem
+	botOpts.Logger = ui.logger
+	go func() {
+		if err := bot.New(botOpts).Play(ctx, t); err != nil && !errors.Is(err, context.Canceled) {
+			ui.logger.Error(err, "ai play error")
+		}
+	}()
+}
+
+// continueGame 从存档继续游戏
+func (ui *GameUI) continueGame() {
+	ui.logrusLogger.SetLevel(logrus.InfoLevel)
+	snapshot, err := loadSnapshot()
+	if err != nil {
+		ui.logger.Error(err, "load saved game error")
+		return
+	}
+	t, err := tetris.RestoreTetris(snapshot, ui.gameOptions())
+	if err != nil {
+		ui.logger.Error(err, "restore saved game error")
+		return
+	}
+	if err := removeSnapshot(); err != nil {
+		ui.logger.Error(err, "remove saved game error")
+	}
+	ui.runGame(t)
+}
+
+// gameOptions 返回根据界面选项创建的游戏选项
+func (ui *GameUI) gameOptions() tetris.Options {
 	opts := tetris.DefaultOptions
+	opts.RandomizerName = ui.opts.Randomizer
+	opts.RotationSystemName = ui.opts.RotationSystem
+	opts.PieceSet = ui.opts.PieceSet
+	opts.Seed = ui.opts.Seed
 	opts.Logger = ui.logger
-	ui.tetris = tetris.NewTetris(opts)
-	go ui.paintGameLoop(ui.tetris.Frames())
+	return opts
+}
+
+// runGame 运行游戏
+func (ui *GameUI) runGame(t tetris.Tetris) {
+	ui.keys.ReleaseAll()
+	ui.tetris = t
+	go ui.paintGameLoop(ui.tetris.SubscribeFrames(tetris.SubscribeOptions{Policy: tetris.PolicyLatest}).C())
+	go ui.handleGameEventLoop(ui.tetris.SubscribeEvents(tetris.SubscribeOptions{
+		Policy:     tetris.PolicyBuffered,
+		BufferSize: 16,
+	}).C())
 	if err := ui.tetris.Start(context.Background()); err != nil {
 		ui.logger.Error(err, "start tetris error")
 		return
@@ -306,6 +461,7 @@ func (ui *GameUI) startGame() {
 // pauseGame 暂停游戏
 func (ui *GameUI) pauseGame() {
 	_ = ui.tetris.Pause()
+	ui.keys.ReleaseAll()
 	if !ui.tetris.Debug() {
 		// 清空显示
 		ui.holdBox.Clear()
@@ -324,15 +480,38 @@ func (ui *GameUI) resumeGame() {
 
 // stopGame 结束游戏
 func (ui *GameUI) stopGame() {
+	if ui.stopBot != nil {
+		ui.stopBot()
+		ui.stopBot = nil
+	}
 	_ = ui.tetris.Stop()
+	ui.keys.ReleaseAll()
 	ui.tetris = nil
 	ui.clearGameInfo()
 	ui.pages.SwitchToPage("main")
 	ui.pages.ShowPage("menu")
 }
 
+// saveAndStopGame 保存并结束游戏
+func (ui *GameUI) saveAndStopGame() {
+	snapshot, err := ui.tetris.Snapshot()
+	if err != nil {
+		ui.logger.Error(err, "save game error")
+		return
+	}
+	if err := saveSnapshot(snapshot); err != nil {
+		ui.logger.Error(err, "save game error")
+		return
+	}
+	ui.stopGame()
+}
+
 // handleGameInput 处理游戏输入
 func (ui *GameUI) handleGameInput(event *tcell.EventKey) *tcell.EventKey {
+	if ui.stopBot != nil && event.Key() != tcell.KeyEnter && event.Key() != tcell.KeyEsc {
+		// 观看 AI 游戏时忽略游戏操作
+		return event
+	}
 	switch event.Key() {
 	case tcell.KeyEnter:
 		// 继续游戏
@@ -347,23 +526,25 @@ func (ui *GameUI) handleGameInput(event *tcell.EventKey) *tcell.EventKey {
 	case tcell.KeyUp:
 		ui.tetris.Input(tetris.OpRotateRight)
 	case tcell.KeyDown:
-		ui.tetris.Input(tetris.OpSoftDrop)
+		ui.keys.Input(ui.tetris, tetris.OpSoftDrop)
 	case tcell.KeyLeft:
-		ui.tetris.Input(tetris.OpMoveLeft)
+		ui.keys.Input(ui.tetris, tetris.OpMoveLeft)
 	case tcell.KeyRight:
-		ui.tetris.Input(tetris.OpMoveRight)
+		ui.keys.Input(ui.tetris, tetris.OpMoveRight)
 	case tcell.KeyRune:
 		switch event.Rune() {
 		case 'w', 'i':
 			ui.tetris.Input(tetris.OpRotateRight)
 		case 'a', 'j':
-			ui.tetris.Input(tetris.OpMoveLeft)
+			ui.keys.Input(ui.tetris, tetris.OpMoveLeft)
 		case 's', 'k':
-			ui.tetris.Input(tetris.OpSoftDrop)
+			ui.keys.Input(ui.tetris, tetris.OpSoftDrop)
 		case 'd', 'l':
-			ui.tetris.Input(tetris.OpMoveRight)
+			ui.keys.Input(ui.tetris, tetris.OpMoveRight)
 		case 'z':
 			ui.tetris.Input(tetris.OpRotateLeft)
+		case 'x':
+			ui.tetris.Input(tetris.OpRotate180)
 		case 'c':
 			ui.tetris.Input(tetris.OpHold)
 		case ' ':
@@ -406,52 +587,46 @@ func (ui *GameUI) paintGameLoop(ch <-chan tetris.Frame) {
 	}
 }
 
+// handleGameEventLoop 处理游戏事件的循环
+func (ui *GameUI) handleGameEventLoop(ch <-chan tetris.Event) {
+	for event := range ch {
+		switch event.Type {
+		case tetris.EventPerfectClear:
+			ui.announce("[yellow]PERFECT\nCLEAR[white]")
+		default:
+		}
+	}
+}
+
+// announce 在画面中短暂显示提示信息
+func (ui *GameUI) announce(text string) {
+	ui.app.QueueUpdateDraw(func() {
+		ui.announceBox.SetText(text)
+	})
+	time.AfterFunc(announceDuration, func() {
+		ui.app.QueueUpdateDraw(func() {
+			if ui.announceBox.GetText(false) == text {
+				ui.announceBox.Clear()
+			}
+		})
+	})
+}
+
 // paintGameFrame 绘制游戏一帧
 func (ui *GameUI) paintGameFrame(frame tetris.Frame) {
 	fieldContent := ""
 	cells := frame.Field.Cells()
 	for i := 19; i >= 0; i-- {
 		for j := 0; j < 10; j++ {
-			if cells[i][j].Shadow {
-				switch cells[i][j].Type {
-				case common.TetrominoNone:
-					fieldContent += "  "
-				case common.I:
-					fieldContent += "[darkcyan]..[black]"
-				case common.J:
-					fieldContent += "[blue]..[black]"
-				case common.L:
-					fieldContent += "[darkorange]..[black]"
-				case common.O:
-					fieldContent += "[orange]..[black]"
-				case common.S:
-					fieldContent += "[lightgreen]..[black]"
-				case common.T:
-					fieldContent += "[mediumpurple]..[black]"
-				case common.Z:
-					fieldContent += "[red]..[black]"
-				}
-			} else {
-				switch cells[i][j].Type {
-				case common.TetrominoNone:
-					fieldContent += "  "
-				case common.I:
-					fieldContent += "[:darkcyan]  [:black]"
-				case common.J:
-					fieldContent += "[:blue]  [:black]"
-				case common.L:
-					fieldContent += "[:darkorange]  [:black]"
-				case common.O:
-					fieldContent += "[:orange]  [:black]"
-				case common.S:
-					fieldContent += "[:lightgreen]  [:black]"
-				case common.T:
-					fieldContent += "[:mediumpurple]  [:black]"
-				case common.Z:
-					fieldContent += "[:red]  [:black]"
-				}
+			cell := cells[i][j]
+			switch {
+			case cell.Type == common.TetrominoNone:
+				fieldContent += "  "
+			case cell.Shadow:
+				fieldContent += "[" + tetrominoColor(cell.Type) + "]..[black]"
+			default:
+				fieldContent += "[:" + tetrominoColor(cell.Type) + "]  [:black]"
 			}
-
 		}
 	}
 	ui.fieldBox.Clear()
@@ -466,17 +641,49 @@ func (ui *GameUI) paintGameFrame(frame tetris.Frame) {
 	ui.levelBox.Clear()
 	_, _ = fmt.Fprintf(ui.levelBox, "%d", frame.Level)
 	ui.linesBox.Clear()
-	_, _ = fmt.Fprintf(ui.linesBox, "%d", frame.ClearLines)
+	if frame.Mode.GoalLines > 0 {
+		_, _ = fmt.Fprintf(ui.linesBox, "%d/%d", frame.ClearLines, frame.Mode.GoalLines)
+	} else {
+		_, _ = fmt.Fprintf(ui.linesBox, "%d", frame.ClearLines)
+	}
+	ui.timeBox.Clear()
+	if frame.Mode.TimeLimit > 0 {
+		// 限时模式显示剩余时间
+		_, _ = fmt.Fprint(ui.timeBox, formatDuration(max(frame.Mode.TimeLimit-frame.Elapsed, 0)))
+	} else {
+		_, _ = fmt.Fprint(ui.timeBox, formatDuration(frame.Elapsed))
+	}
 	ui.nextBox.Clear()
 	for _, b := range frame.NextTetrominoes {
 		_, _ = fmt.Fprint(ui.nextBox, paintTetrisTetromino(b))
 	}
+	ui.comboBox.Clear()
+	if frame.BackToBack {
+		_, _ = fmt.Fprint(ui.comboBox, "[yellow]B2B[white]")
+	}
+	if frame.Combo > 0 {
+		_, _ = fmt.Fprintf(ui.comboBox, "\nCombo %d", frame.Combo)
+	}
+	ui.statsBox.Clear()
+	_, _ = fmt.Fprintf(
+		ui.statsBox, "PPS %6.2f\nKPP %6.2f\nAPM %6.1f\nPCS %6d\nFIN %6d",
+		frame.Stats.PPS(), frame.Stats.KPP(), frame.Stats.APM(), frame.Stats.Pieces, frame.Stats.FinesseFaults,
+	)
 
 	// 游戏结束
 	if frame.GameOver {
+		title := "Game Over"
+		if frame.Result == tetris.ResultWon {
+			title = frame.Mode.Name + " Cleared"
+		}
+		topOut := ""
+		if frame.TopOut != tetris.TopOutNone {
+			topOut = "[red]" + frame.TopOut.String() + "[white]\n"
+		}
+		ui.gameOverBox.SetTitle(title)
 		ui.gameOverBox.SetText(fmt.Sprintf(
-			"\nScore: %d\n\n[lightgray](Press ENTER or ESC to continue)[white]",
-			frame.Score,
+			"\n%sScore: %d\nLines: %d\nTime: %s\nSeed: %d\n\n[lightgray](Press ENTER or ESC to continue)[white]",
+			topOut, frame.Score, frame.ClearLines, formatDuration(frame.Elapsed), frame.Seed,
 		))
 		ui.pages.ShowPage("over")
 	}
@@ -488,52 +695,170 @@ func (ui *GameUI) clearGameInfo() {
 	ui.scoreBox.Clear()
 	ui.levelBox.Clear()
 	ui.linesBox.Clear()
+	ui.timeBox.Clear()
+	ui.announceBox.Clear()
+	ui.comboBox.Clear()
+	ui.statsBox.Clear()
 	ui.stateBox.Clear()
 	ui.nextBox.Clear()
 	ui.fieldBox.Clear()
 }
 
+// tetrominoColors 内置方块的颜色
+var tetrominoColors = map[common.TetrominoType]string{
+	common.I:       "darkcyan",
+	common.J:       "blue",
+	common.L:       "darkorange",
+	common.O:       "orange",
+	common.S:       "lightgreen",
+	common.T:       "mediumpurple",
+	common.Z:       "red",
+	common.Garbage: "gray",
+}
+
+// tetrominoColor 返回方块的颜色，非内置方块使用方块定义中的颜色
+func tetrominoColor(tetrominoType common.TetrominoType) string {
+	if color, ok := tetrominoColors[tetrominoType]; ok {
+		return color
+	}
+	if def, ok := common.Piece(tetrominoType); ok && def.Color != "" {
+		return def.Color
+	}
+	return "white"
+}
+
+// previewWidth 暂存和下一个方块预览区域的宽度（字符数）
+const previewWidth = 10
+
 // paintTetrisTetromino 绘制方块
+//
+// 绘制方块初始方向的形状，共两行。不超过 5 列 2 行的方块每格占两个字符，更大的方块用半格字符缩小绘制
 func paintTetrisTetromino(tetrominoType common.TetrominoType) string {
-	switch tetrominoType {
-	case common.TetrominoNone:
-	case common.I:
-		return `
-
- [:darkcyan]        [:black]
-`
-	case common.J:
-		return `
-  [:blue]  [:black]
-  [:blue]      [:black]
-`
-	case common.L:
-		return `
-      [:darkorange]  [:black]
-  [:darkorange]      [:black]
-`
-	case common.O:
-		return `
-   [:orange]    [:black]
-   [:orange]    [:black]
-`
-	case common.S:
-		return `
-    [:lightgreen]    [:black]
-  [:lightgreen]    [:black]
-`
-	case common.T:
-		return `
-    [:mediumpurple]  [:black]
-  [:mediumpurple]      [:black]
-`
-	case common.Z:
-		return `
-  [:red]    [:black]
-    [:red]    [:black]
-`
-	}
-	return ""
+	if !tetrominoType.IsPiece() {
+		return ""
+	}
+
+	// 转换为从上往下的网格
+	cells := common.Tetromino{Type: tetrominoType}.Cells()
+	top, bottom, left, right := cells[0].Row(), cells[0].Row(), cells[0].Column(), cells[0].Column()
+	for _, cell := range cells[1:] {
+		top, bottom = max(top, cell.Row()), min(bottom, cell.Row())
+		left, right = min(left, cell.Column()), max(right, cell.Column())
+	}
+	height, width := top-bottom+1, right-left+1
+	grid := make([][]bool, height)
+	for i := range grid {
+		grid[i] = make([]bool, width)
+	}
+	for _, cell := range cells {
+		grid[top-cell.Row()][cell.Column()-left] = true
+	}
+
+	color := tetrominoColor(tetrominoType)
+	var lines []string
+	if width <= previewWidth/2 && height <= 2 {
+		for _, row := range grid {
+			line := strings.Repeat(" ", (previewWidth-2*width)/2)
+			filled := false
+			for _, f := range row {
+				switch {
+				case f && !filled:
+					line += "[:" + color + "]"
+				case !f && filled:
+					line += "[:black]"
+				}
+				filled = f
+				line += "  "
+			}
+			if filled {
+				line += "[:black]"
+			}
+			lines = append(lines, strings.TrimRight(line, " "))
+		}
+	} else {
+		for i := 0; i < height; i += 2 {
+			line := strings.Repeat(" ", max(previewWidth-width, 0)/2) + "[" + color + "]"
+			for j := range width {
+				upper, lower := grid[i][j], i+1 < height && grid[i+1][j]
+				switch {
+				case upper && lower:
+					line += "█"
+				case upper:
+					line += "▀"
+				case lower:
+					line += "▄"
+				default:
+					line += " "
+				}
+			}
+			lines = append(lines, line+"[white]")
+		}
+	}
+	for len(lines) < 2 {
+		lines = append([]string{""}, lines...)
+	}
+	return "\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// formatDuration 格式化时长为 分:秒.百分秒
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%d:%05.2f", int(d.Minutes()), (d % time.Minute).Seconds())
+}
+
+// padCenter 在文本两侧填充空格使其居中于指定宽度
+func padCenter(text string, width int) string {
+	if len(text) >= width {
+		return text
+	}
+	left := (width - len(text)) / 2
+	return strings.Repeat(" ", left) + text + strings.Repeat(" ", width-len(text)-left)
+}
+
+// snapshotPath 返回存档文件路径
+func snapshotPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-tetris", "save.json"), nil
+}
+
+// saveSnapshot 保存存档到文件
+func saveSnapshot(snapshot tetris.Snapshot) error {
+	p, err := snapshotPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+// loadSnapshot 从文件加载存档
+func loadSnapshot() (tetris.Snapshot, error) {
+	p, err := snapshotPath()
+	if err != nil {
+		return tetris.Snapshot{}, err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return tetris.Snapshot{}, err
+	}
+	return tetris.UnmarshalSnapshot(data)
+}
+
+// removeSnapshot 删除存档文件
+func removeSnapshot() error {
+	p, err := snapshotPath()
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
 }
 
 // logFormatter 日志格式化器
@@ -1,12 +1,26 @@
 package web
 
 import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 
 	"github.com/yhlooo/go-tetris/pkg/tetris"
 	"github.com/yhlooo/go-tetris/pkg/tetris/common"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
 )
 
+// snapshotStorageKey 存档在浏览器本地存储中的键
+const snapshotStorageKey = "tetris-save"
+
+// announceDuration 提示信息显示时长
+const announceDuration = 2 * time.Second
+
 // handleInput 处理用户输入事件
 func (ui *GameUI) handleInput(ctx app.Context, e app.Value) {
 	if ui.tetris == nil {
@@ -15,19 +29,25 @@ func (ui *GameUI) handleInput(ctx app.Context, e app.Value) {
 
 	keyCode := e.Get("key").String()
 	app.Logf("key down: %q\n", keyCode)
+	if e.Get("repeat").Bool() {
+		// 按住时的自动重复由游戏根据 DAS 、 ARR 处理，忽略系统的按键重复
+		return
+	}
 	switch keyCode {
 	case "ArrowUp", "w", "i":
 		ui.tetris.Input(tetris.OpRotateRight)
 	case "ArrowDown", "s", "k":
-		ui.tetris.Input(tetris.OpSoftDrop)
+		ui.tetris.Input(tetris.OpSoftDropPress)
 	case "ArrowLeft", "a", "j":
-		ui.tetris.Input(tetris.OpMoveLeft)
+		ui.tetris.Input(tetris.OpMoveLeftPress)
 	case "ArrowRight", "d", "l":
-		ui.tetris.Input(tetris.OpMoveRight)
+		ui.tetris.Input(tetris.OpMoveRightPress)
 	case " ":
 		ui.tetris.Input(tetris.OpHardDrop)
 	case "z":
 		ui.tetris.Input(tetris.OpRotateLeft)
+	case "x":
+		ui.tetris.Input(tetris.OpRotate180)
 	case "c":
 		ui.tetris.Input(tetris.OpHold)
 	case "Enter":
@@ -43,6 +63,24 @@ func (ui *GameUI) handleInput(ctx app.Context, e app.Value) {
 	ctx.Update()
 }
 
+// handleInputRelease 处理用户松开按键事件
+func (ui *GameUI) handleInputRelease(_ app.Context, e app.Value) {
+	if ui.tetris == nil {
+		return
+	}
+
+	keyCode := e.Get("key").String()
+	app.Logf("key up: %q\n", keyCode)
+	switch keyCode {
+	case "ArrowDown", "s", "k":
+		ui.tetris.Input(tetris.OpSoftDropRelease)
+	case "ArrowLeft", "a", "j":
+		ui.tetris.Input(tetris.OpMoveLeftRelease)
+	case "ArrowRight", "d", "l":
+		ui.tetris.Input(tetris.OpMoveRightRelease)
+	}
+}
+
 // paintFrameLoop 绘制游戏帧循环
 func (ui *GameUI) paintFrameLoop(ctx app.Context, ch <-chan tetris.Frame) {
 	for frame := range ch {
@@ -52,7 +90,8 @@ func (ui *GameUI) paintFrameLoop(ctx app.Context, ch <-chan tetris.Frame) {
 
 // paintFrame 绘制帧
 func (ui *GameUI) paintFrame(ctx app.Context, frame tetris.Frame) {
-	ui.field.UpdateTetrominoes(frame.Field.Cells())
+	// 只显示可见行
+	ui.field.UpdateTetrominoes(frame.Field.Cells()[:frame.VisibleRows])
 	ui.next[0].UpdateTetrominoes(newTetrominoGridData(frame.NextTetrominoes[0]))
 	ui.next[1].UpdateTetrominoes(newTetrominoGridData(frame.NextTetrominoes[1]))
 	ui.next[2].UpdateTetrominoes(newTetrominoGridData(frame.NextTetrominoes[2]))
@@ -65,6 +104,14 @@ func (ui *GameUI) paintFrame(ctx app.Context, frame tetris.Frame) {
 	ui.score = frame.Score
 	ui.level = frame.Level
 	ui.clearLines = frame.ClearLines
+	ui.combo = frame.Combo
+	ui.backToBack = frame.BackToBack
+	ui.stats = frame.Stats
+	ui.mode = frame.Mode
+	ui.gameSeed = frame.Seed
+	ui.elapsed = frame.Elapsed
+	ui.result = frame.Result
+	ui.topOut = frame.TopOut
 
 	if frame.GameOver {
 		ui.toGameOver(ctx)
@@ -73,9 +120,33 @@ func (ui *GameUI) paintFrame(ctx app.Context, frame tetris.Frame) {
 	ctx.Update()
 }
 
+// handleEventLoop 处理游戏事件循环
+func (ui *GameUI) handleEventLoop(ctx app.Context, ch <-chan tetris.Event) {
+	for event := range ch {
+		switch event.Type {
+		case tetris.EventPerfectClear:
+			ui.announce(ctx, "PERFECT CLEAR")
+		default:
+		}
+	}
+}
+
+// announce 在游戏场中短暂显示提示信息
+func (ui *GameUI) announce(ctx app.Context, text string) {
+	ctx.Dispatch(func(ctx app.Context) {
+		ui.announcement = text
+	})
+	ctx.After(announceDuration, func(ctx app.Context) {
+		if ui.announcement == text {
+			ui.announcement = ""
+		}
+	})
+}
+
 // toStartMenu 回到开始菜单
 func (ui *GameUI) toStartMenu(_ app.Context) {
 	ui.page = ""
+	ui.announcement = ""
 	if ui.tetris != nil {
 		if err := ui.tetris.Stop(); err != nil {
 			app.Logf("stop tetris error: %v", err)
@@ -85,16 +156,23 @@ func (ui *GameUI) toStartMenu(_ app.Context) {
 	}
 }
 
+// toModeMenu 进入游戏模式选择菜单
+func (ui *GameUI) toModeMenu(_ app.Context) {
+	ui.page = "modes"
+}
+
+// toNewGame 以指定模式开始新游戏
+func (ui *GameUI) toNewGame(ctx app.Context, mode tetris.GameMode) {
+	opts := ui.gameOptions()
+	opts.Mode = mode
+	ui.startGame(ctx, tetris.NewTetris(opts))
+	ui.page = "game"
+}
+
 // toGame 开始或回到游戏
 func (ui *GameUI) toGame(ctx app.Context) {
 	if ui.tetris == nil {
-		ui.tetris = tetris.NewTetris(tetris.DefaultOptions)
-		ui.touchController.SetTetris(ui.tetris)
-		go ui.paintFrameLoop(ctx, ui.tetris.Frames())
-		if err := ui.tetris.Start(ctx); err != nil {
-			app.Logf("start tetris error: %v", err)
-			return
-		}
+		ui.startGame(ctx, tetris.NewTetris(ui.gameOptions()))
 	}
 	if ui.tetris.State() == tetris.StatePaused {
 		if err := ui.tetris.Resume(); err != nil {
@@ -106,6 +184,96 @@ func (ui *GameUI) toGame(ctx app.Context) {
 	ui.page = "game"
 }
 
+// toSavedGame 从存档继续游戏
+func (ui *GameUI) toSavedGame(ctx app.Context) {
+	var data json.RawMessage
+	if err := ctx.LocalStorage().Get(snapshotStorageKey, &data); err != nil {
+		app.Logf("load saved game error: %v", err)
+		return
+	}
+	snapshot, err := tetris.UnmarshalSnapshot(data)
+	if err != nil {
+		app.Logf("load saved game error: %v", err)
+		return
+	}
+	t, err := tetris.RestoreTetris(snapshot, tetris.DefaultOptions)
+	if err != nil {
+		app.Logf("restore saved game error: %v", err)
+		return
+	}
+	ctx.LocalStorage().Del(snapshotStorageKey)
+	ui.hasSave = false
+
+	ui.startGame(ctx, t)
+	ui.page = "game"
+}
+
+// toSaveAndQuit 保存游戏并回到开始菜单
+func (ui *GameUI) toSaveAndQuit(ctx app.Context) {
+	if ui.tetris != nil {
+		snapshot, err := ui.tetris.Snapshot()
+		if err != nil {
+			app.Logf("save game error: %v", err)
+			return
+		}
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			app.Logf("save game error: %v", err)
+			return
+		}
+		if err := ctx.LocalStorage().Set(snapshotStorageKey, json.RawMessage(data)); err != nil {
+			app.Logf("save game error: %v", err)
+			return
+		}
+		ui.hasSave = true
+	}
+	ui.toStartMenu(ctx)
+}
+
+// gameOptions 返回新游戏的选项
+func (ui *GameUI) gameOptions() tetris.Options {
+	opts := tetris.DefaultOptions
+	opts.Seed = ui.seed
+	opts.RotationSystemName = ui.rotationSystem
+	opts.PieceSet = ui.pieceSet
+	return opts
+}
+
+// seedLink 返回以上一局的种子和设置开始新游戏的链接
+func (ui *GameUI) seedLink() string {
+	query := url.Values{}
+	query.Set(seedParam, strconv.FormatUint(ui.gameSeed, 10))
+	query.Set(rotationParam, ui.rotationSystem)
+	query.Set(piecesParam, ui.pieceSet)
+	return "?" + query.Encode()
+}
+
+// switchRotationSystem 切换到下一个内置旋转系统
+func (ui *GameUI) switchRotationSystem(_ app.Context) {
+	i := slices.Index(rotationsystems.Names, ui.rotationSystem)
+	ui.rotationSystem = rotationsystems.Names[(i+1)%len(rotationsystems.Names)]
+}
+
+// switchPieceSet 切换到下一个内置方块集合
+func (ui *GameUI) switchPieceSet(_ app.Context) {
+	i := slices.Index(pieces.Names, ui.pieceSet)
+	ui.pieceSet = pieces.Names[(i+1)%len(pieces.Names)]
+}
+
+// startGame 开始运行指定游戏
+func (ui *GameUI) startGame(ctx app.Context, t tetris.Tetris) {
+	ui.tetris = t
+	ui.touchController.SetTetris(ui.tetris)
+	go ui.paintFrameLoop(ctx, ui.tetris.SubscribeFrames(tetris.SubscribeOptions{Policy: tetris.PolicyLatest}).C())
+	go ui.handleEventLoop(ctx, ui.tetris.SubscribeEvents(tetris.SubscribeOptions{
+		Policy:     tetris.PolicyBuffered,
+		BufferSize: 16,
+	}).C())
+	if err := ui.tetris.Start(ctx); err != nil {
+		app.Logf("start tetris error: %v", err)
+	}
+}
+
 // toGameOver 游戏结束
 func (ui *GameUI) toGameOver(_ app.Context) {
 	ui.page = "over"
@@ -141,57 +141,30 @@ func (grid *TetrisGrid) paintBorder() {
 }
 
 // newTetrominoGridData 创建方块网格数据
+//
+// 网格为方块初始方向的形状，第 0 行为最下方一行，至少两行
 func newTetrominoGridData(tetrominoType common.TetrominoType) [][]common.Cell {
-	i := common.Cell{Type: common.I}
-	j := common.Cell{Type: common.J}
-	l := common.Cell{Type: common.L}
-	o := common.Cell{Type: common.O}
-	s := common.Cell{Type: common.S}
-	t := common.Cell{Type: common.T}
-	z := common.Cell{Type: common.Z}
-	none := common.Cell{}
-	switch tetrominoType {
-	case common.I:
-		return [][]common.Cell{
-			{i, i, i, i},
-			{none, none, none, none},
-		}
-	case common.J:
-		return [][]common.Cell{
-			{j, j, j},
-			{j, none, none},
-		}
-	case common.L:
-		return [][]common.Cell{
-			{l, l, l},
-			{none, none, l},
-		}
-	case common.O:
-		return [][]common.Cell{
-			{o, o},
-			{o, o},
-		}
-	case common.S:
+	if !tetrominoType.IsPiece() {
 		return [][]common.Cell{
-			{s, s, none},
-			{none, s, s},
-		}
-	case common.T:
-		return [][]common.Cell{
-			{t, t, t},
-			{none, t, none},
-		}
-	case common.Z:
-		return [][]common.Cell{
-			{none, z, z},
-			{z, z, none},
-		}
-	default:
-		return [][]common.Cell{
-			{none, none, none},
-			{none, none, none},
+			make([]common.Cell, 3),
+			make([]common.Cell, 3),
 		}
 	}
+
+	cells := common.Tetromino{Type: tetrominoType}.Cells()
+	top, bottom, left, right := cells[0].Row(), cells[0].Row(), cells[0].Column(), cells[0].Column()
+	for _, cell := range cells[1:] {
+		top, bottom = max(top, cell.Row()), min(bottom, cell.Row())
+		left, right = min(left, cell.Column()), max(right, cell.Column())
+	}
+	data := make([][]common.Cell, max(top-bottom+1, 2))
+	for i := range data {
+		data[i] = make([]common.Cell, right-left+1)
+	}
+	for _, cell := range cells {
+		data[cell.Row()-bottom][cell.Column()-left] = common.Cell{Type: tetrominoType}
+	}
+	return data
 }
 
 // TetrominoColors 方块颜色
@@ -205,6 +178,7 @@ type TetrominoColors struct {
 	TetrominoS string
 	TetrominoT string
 	TetrominoZ string
+	Garbage    string
 }
 
 // Tetromino 获取指定方块颜色
@@ -226,6 +200,12 @@ func (colors TetrominoColors) Tetromino(tetrominoType common.TetrominoType) stri
 		return colors.TetrominoT
 	case common.Z:
 		return colors.TetrominoZ
+	case common.Garbage:
+		return colors.Garbage
+	}
+	// 非内置方块使用方块定义中的颜色
+	if def, ok := common.Piece(tetrominoType); ok && def.Color != "" {
+		return def.Color
 	}
 	return colors.Background
 }
@@ -241,4 +221,5 @@ var DefaultTetrominoColors = TetrominoColors{
 	TetrominoS: "#62b451",
 	TetrominoT: "#a25399",
 	TetrominoZ: "#db3e32",
+	Garbage:    "#6b6b6b",
 }
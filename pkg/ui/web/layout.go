@@ -2,9 +2,16 @@ package web
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
+
+	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/common"
 )
 
 // renderMain 渲染主要内容
@@ -44,33 +51,84 @@ func (ui *GameUI) renderGame() app.UI {
 				),
 				app.Div().Body(
 					app.Div().Class("tetris-game-sub-title").Text("LINES"),
-					app.Div().Text(strconv.Itoa(ui.clearLines)),
+					app.Div().Text(ui.linesText()),
+				),
+				app.Div().Body(
+					app.Div().Class("tetris-game-sub-title").Text("TIME"),
+					app.Div().Text(ui.timeText()),
+				),
+				app.Div().Body(
+					app.Div().Class("tetris-game-sub-title").Text("COMBO"),
+					app.Div().Text(strconv.Itoa(ui.combo)),
+					app.If(ui.backToBack, func() app.UI {
+						return app.Div().Class("tetris-b2b").Text("B2B")
+					}),
 				),
 			),
 		),
 		app.Div().Class("tetris-game-field").Body(
 			app.If(ui.page == "", func() app.UI {
 				return app.Div().Class("tetris-game-menu").Body(
-					app.Button().Text("Start").OnClick(func(ctx app.Context, _ app.Event) { ui.toGame(ctx) }),
+					app.Button().Text("Start").OnClick(func(ctx app.Context, _ app.Event) { ui.toModeMenu(ctx) }),
+					app.If(ui.hasSave, func() app.UI {
+						return app.Button().Text("Continue").OnClick(func(ctx app.Context, _ app.Event) { ui.toSavedGame(ctx) })
+					}),
 					app.Button().Text("Help").OnClick(func(ctx app.Context, _ app.Event) { ui.showHelp = true }),
 					app.Button().Text("About").OnClick(func(ctx app.Context, _ app.Event) { ui.showAbout = true }),
 				)
+			}).ElseIf(ui.page == "modes", func() app.UI {
+				return app.Div().Class("tetris-game-menu").Body(
+					app.Range(tetris.BuiltinGameModes).Slice(func(i int) app.UI {
+						mode := tetris.BuiltinGameModes[i]
+						return app.Button().Text(mode.Name).Title(mode.Goal()).OnClick(func(ctx app.Context, _ app.Event) {
+							ui.toNewGame(ctx, mode)
+						})
+					}),
+					app.Button().Text("Rotation: "+strings.ToUpper(ui.rotationSystem)).
+						Title("Switch rotation system (SRS: guideline, ARS: TGM, NRS: NES)").
+						OnClick(func(ctx app.Context, _ app.Event) { ui.switchRotationSystem(ctx) }),
+					app.Button().Text("Pieces: "+strings.ToUpper(ui.pieceSet[:1])+ui.pieceSet[1:]).
+						Title("Switch piece set (Tetromino, Pentomino, Big, Tiny)").
+						OnClick(func(ctx app.Context, _ app.Event) { ui.switchPieceSet(ctx) }),
+					app.Button().Text("Back").OnClick(func(ctx app.Context, _ app.Event) { ui.toStartMenu(ctx) }),
+				)
 			}).ElseIf(ui.page == "paused", func() app.UI {
 				return app.Div().Class("tetris-game-menu").Body(
 					app.Button().Text("Resume").OnClick(func(ctx app.Context, _ app.Event) { ui.toGame(ctx) }),
 					app.Button().Text("Help").OnClick(func(ctx app.Context, _ app.Event) { ui.showHelp = true }),
 					app.Button().Text("About").OnClick(func(ctx app.Context, _ app.Event) { ui.showAbout = true }),
+					app.Button().Text("Save & Quit").OnClick(func(ctx app.Context, _ app.Event) { ui.toSaveAndQuit(ctx) }),
 					app.Button().Text("Quit").OnClick(func(ctx app.Context, _ app.Event) { ui.toStartMenu(ctx) }),
 				)
 			}).ElseIf(ui.page == "over", func() app.UI {
+				title := "Game Over"
+				if ui.result == tetris.ResultWon {
+					title = ui.mode.Name + " Cleared"
+				}
 				return app.Div().Class("tetris-game-menu").Body(
-					app.Div().Class("tetris-game-sub-title").Text("Game Over"),
-					app.Div().Text(fmt.Sprintf("Score: %d", ui.score)).Style("margin-bottom", "15px"),
+					app.Div().Class("tetris-game-sub-title").Text(title),
+					app.If(ui.topOut != tetris.TopOutNone, func() app.UI {
+						return app.Div().Text(ui.topOut.String())
+					}),
+					app.Div().Text(fmt.Sprintf("Score: %d", ui.score)),
+					app.Div().Text(fmt.Sprintf("Lines: %d", ui.clearLines)),
+					app.Div().Text("Time: "+formatDuration(ui.elapsed)),
+					app.Div().Style("margin-bottom", "15px").Body(
+						app.Text("Seed: "),
+						app.A().
+							Href(ui.seedLink()).
+							Title("Play again with the same piece sequence").
+							Text(strconv.FormatUint(ui.gameSeed, 10)),
+					),
+					ui.renderStats(),
 					app.Button().Text("Ok").OnClick(func(ctx app.Context, _ app.Event) { ui.toStartMenu(ctx) }),
 				)
 			}).Else(func() app.UI {
 				return ui.field
 			}),
+			app.If(ui.page == "game" && ui.announcement != "", func() app.UI {
+				return app.Div().Class("tetris-announcement").Text(ui.announcement)
+			}),
 		).Styles(map[string]string{
 			"width":  strconv.Itoa(fieldWidth) + "px",
 			"height": strconv.Itoa(fieldHeight) + "px",
@@ -95,6 +153,66 @@ func (ui *GameUI) renderGame() app.UI {
 	)
 }
 
+// renderStats 渲染统计数据
+func (ui *GameUI) renderStats() app.UI {
+	stats := ui.stats
+	items := [][2]string{
+		{"Pieces", strconv.Itoa(stats.Pieces)},
+		{"PPS", fmt.Sprintf("%.2f", stats.PPS())},
+		{"KPP", fmt.Sprintf("%.2f", stats.KPP())},
+		{"APM", fmt.Sprintf("%.1f", stats.APM())},
+		{"Singles", strconv.Itoa(stats.Singles)},
+		{"Doubles", strconv.Itoa(stats.Doubles)},
+		{"Triples", strconv.Itoa(stats.Triples)},
+		{"Tetrises", strconv.Itoa(stats.Tetrises)},
+		{"5+ Lines", strconv.Itoa(stats.FivePlusClears)},
+		{"T-Spins", strconv.Itoa(stats.TSpins)},
+		{"Perfect Clears", strconv.Itoa(stats.PerfectClears)},
+		{"Max Combo", strconv.Itoa(stats.MaxCombo)},
+		{"Finesse Faults", strconv.Itoa(stats.FinesseFaults)},
+	}
+	pieces := ""
+	for t := common.I; t <= common.Z; t++ {
+		pieces += fmt.Sprintf("%s:%d ", t, stats.PieceCounts[t])
+	}
+	// 自定义方块只显示放置过的
+	for _, t := range slices.Sorted(maps.Keys(stats.PieceCounts)) {
+		if t > common.Z {
+			pieces += fmt.Sprintf("%s:%d ", t, stats.PieceCounts[t])
+		}
+	}
+	return app.Div().Class("tetris-stats").Body(
+		app.Range(items).Slice(func(i int) app.UI {
+			return app.Div().Body(
+				app.Span().Text(items[i][0]),
+				app.Span().Text(items[i][1]),
+			)
+		}),
+		app.Div().Class("tetris-stats-pieces").Text(strings.TrimSpace(pieces)),
+	)
+}
+
+// linesText 返回已消除行数文本
+func (ui *GameUI) linesText() string {
+	if ui.mode.GoalLines > 0 {
+		return fmt.Sprintf("%d/%d", ui.clearLines, ui.mode.GoalLines)
+	}
+	return strconv.Itoa(ui.clearLines)
+}
+
+// timeText 返回计时文本，限时模式下为剩余时间
+func (ui *GameUI) timeText() string {
+	if ui.mode.TimeLimit > 0 {
+		return formatDuration(max(ui.mode.TimeLimit-ui.elapsed, 0))
+	}
+	return formatDuration(ui.elapsed)
+}
+
+// formatDuration 格式化时长为 分:秒.百分秒
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%d:%05.2f", int(d.Minutes()), (d % time.Minute).Seconds())
+}
+
 // renderHelp 渲染帮助信息
 func (ui *GameUI) renderHelp() app.UI {
 	return app.Div().Class("tetris-help tetris-tip-box").Body(
@@ -106,6 +224,7 @@ func (ui *GameUI) renderHelp() app.UI {
 				app.Text("Right / d / l : Move Right"), app.Br(),
 				app.Text("Down / s / k : Soft Drop"), app.Br(),
 				app.Text("z : Rotate Left"), app.Br(),
+				app.Text("x : Rotate 180"), app.Br(),
 				app.Text("c : Hold"), app.Br(),
 				app.Text("Space : Hard Drop"), app.Br(),
 				app.Text("ESC : Pause"),
@@ -1,15 +1,33 @@
 package web
 
 import (
+	"slices"
+	"strconv"
+	"time"
+
 	"github.com/maxence-charriere/go-app/v10/pkg/app"
 
 	"github.com/yhlooo/go-tetris/pkg/tetris"
+	"github.com/yhlooo/go-tetris/pkg/tetris/pieces"
+	"github.com/yhlooo/go-tetris/pkg/tetris/rotationsystems"
+)
+
+// URL 参数名
+const (
+	// seedParam 指定随机种子的 URL 参数名
+	seedParam = "seed"
+	// rotationParam 指定旋转系统的 URL 参数名
+	rotationParam = "rotation"
+	// piecesParam 指定方块集合的 URL 参数名
+	piecesParam = "pieces"
 )
 
 // NewGameUI 创建 GameUI
 func NewGameUI() *GameUI {
 	return &GameUI{
 		touchController: &TouchController{},
+		rotationSystem:  rotationsystems.NameSRS,
+		pieceSet:        pieces.NameTetromino,
 	}
 }
 
@@ -18,6 +36,7 @@ type GameUI struct {
 	app.Compo
 
 	handleKeyDown   app.Func
+	handleKeyUp     app.Func
 	touchController *TouchController
 
 	field      *TetrisGrid
@@ -26,10 +45,28 @@ type GameUI struct {
 	score      int
 	level      int
 	clearLines int
+	combo      int
+	backToBack bool
+	stats      tetris.Stats
+	mode       tetris.GameMode
+	gameSeed   uint64
+	elapsed    time.Duration
+	result     tetris.GameResult
+	topOut     tetris.TopOutType
+
+	// 由 URL 参数 seed 指定的随机种子， 0 表示每局使用随机的种子
+	seed uint64
+	// 旋转系统名，可在模式选择菜单切换或由 URL 参数 rotation 指定
+	rotationSystem string
+	// 方块集合名，可在模式选择菜单切换或由 URL 参数 pieces 指定
+	pieceSet string
+
+	announcement string
 
 	page      string
 	showHelp  bool
 	showAbout bool
+	hasSave   bool
 
 	tetris tetris.Tetris
 }
@@ -68,10 +105,49 @@ func (ui *GameUI) OnMount(ctx app.Context) {
 		return nil
 	})
 	app.Window().Call("addEventListener", "keydown", ui.handleKeyDown)
+	ui.handleKeyUp = app.FuncOf(func(this app.Value, args []app.Value) any {
+		ui.handleInputRelease(ctx, args[0])
+		return nil
+	})
+	app.Window().Call("addEventListener", "keyup", ui.handleKeyUp)
+
+	ui.hasSave = ctx.LocalStorage().Contains(snapshotStorageKey)
+}
+
+// OnNav 导航到该页面时
+func (ui *GameUI) OnNav(ctx app.Context) {
+	ui.seed = 0
+	if v := ctx.Page().URL().Query().Get(seedParam); v != "" {
+		seed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			app.Logf("invalid seed %q: %v", v, err)
+			seed = 0
+		}
+		ui.seed = seed
+	}
+	if v := ctx.Page().URL().Query().Get(rotationParam); v != "" {
+		if slices.Contains(rotationsystems.Names, v) {
+			ui.rotationSystem = v
+		} else {
+			app.Logf("invalid rotation system %q", v)
+		}
+	}
+	if v := ctx.Page().URL().Query().Get(piecesParam); v != "" {
+		if slices.Contains(pieces.Names, v) {
+			ui.pieceSet = v
+		} else {
+			app.Logf("invalid piece set %q", v)
+		}
+	}
+	if ui.page == "over" {
+		// 从游戏结束页的种子链接进入，回到开始菜单以便用该种子开始新游戏
+		ui.toStartMenu(ctx)
+	}
 }
 
 // OnDismount 卸载元素时
 func (ui *GameUI) OnDismount() {
 	app.Log("tetris component dismount")
 	app.Window().Call("removeEventListener", "keydown", ui.handleKeyDown)
+	app.Window().Call("removeEventListener", "keyup", ui.handleKeyUp)
 }